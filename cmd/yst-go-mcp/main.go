@@ -1,26 +1,92 @@
 package main
 
 import (
+	"archive/zip"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/Xuzan9396/yst_go_mcp/internal/analysis"
+	"github.com/Xuzan9396/yst_go_mcp/internal/archive"
 	"github.com/Xuzan9396/yst_go_mcp/internal/browser"
+	"github.com/Xuzan9396/yst_go_mcp/internal/calendar"
 	"github.com/Xuzan9396/yst_go_mcp/internal/collector"
 	"github.com/Xuzan9396/yst_go_mcp/internal/cookie"
+	"github.com/Xuzan9396/yst_go_mcp/internal/daterange"
+	"github.com/Xuzan9396/yst_go_mcp/internal/draft"
+	"github.com/Xuzan9396/yst_go_mcp/internal/export"
+	"github.com/Xuzan9396/yst_go_mcp/internal/fileutil"
+	"github.com/Xuzan9396/yst_go_mcp/internal/jobs"
+	"github.com/Xuzan9396/yst_go_mcp/internal/migrate"
+	"github.com/Xuzan9396/yst_go_mcp/internal/paths"
+	"github.com/Xuzan9396/yst_go_mcp/internal/search"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// summaryTaskLimit 月度汇总表建议的最多任务数
+const summaryTaskLimit = 8
+
+// jobManager 后台任务管理器，启动时加载状态目录中的历史任务
+var jobManager *jobs.Manager
+
 func main() {
+	// 命令行参数覆盖默认目录，优先级高于环境变量
+	var dirs paths.Dirs
+	flag.StringVar(&dirs.Config, "config-dir", "", "配置目录（draft.json、keywords.json、holidays.json），也可用环境变量 "+paths.ConfigDirEnv)
+	flag.StringVar(&dirs.Data, "data-dir", "", "数据目录（Cookie、浏览器配置、搜索索引、历史版本），也可用环境变量 "+paths.DataDirEnv)
+	flag.StringVar(&dirs.Cache, "cache-dir", "", "缓存目录，也可用环境变量 "+paths.CacheDirEnv)
+	flag.StringVar(&dirs.State, "state-dir", "", "状态目录（后台任务、采集检查点），也可用环境变量 "+paths.StateDirEnv)
+	flag.StringVar(&dirs.Output, "output-dir", "", "默认输出目录，也可用环境变量 "+paths.OutputDirEnv)
+	flag.StringVar(&dirs.Profile, "profile", "", "账号配置名（默认 default），也可用环境变量 "+paths.ProfileEnv)
+	migrateDryRun := flag.Bool("migrate-dry-run", false, "只列出数据目录迁移将要执行的操作，不做修改并退出")
+	flag.Parse()
+	paths.Override(dirs)
+
+	// 迁移数据目录布局
+	result, err := migrate.Run(paths.Data(), *migrateDryRun)
+	if *migrateDryRun {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "迁移检查失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(result.String())
+		return
+	}
+	if err != nil {
+		log.Printf("⚠️ 数据目录迁移失败: %v", err)
+	} else if result.From != result.To {
+		log.Print(result.String())
+	}
+
+	dirs = paths.All()
+	log.Printf("配置目录: %s", dirs.Config)
+	log.Printf("数据目录: %s", dirs.Data)
+	log.Printf("状态目录: %s", dirs.State)
+	log.Printf("输出目录: %s", dirs.Output)
+	log.Printf("账号配置: %s", dirs.Profile)
+
 	// 创建 MCP Server
 	mcpServer := server.NewMCPServer(
 		"YST Go MCP",
 		"0.0.3",
 	)
 
+	// 加载后台任务
+	if jobManager, err = jobs.NewManager(paths.State()); err != nil {
+		log.Printf("⚠️ 后台任务不可用: %v", err)
+	}
+
 	// 注册工具
 	registerTools(mcpServer)
 
@@ -42,6 +108,10 @@ func registerTools(s *server.MCPServer) {
 				mcp.DefaultNumber(360),
 				mcp.Description("登录超时时间（秒），默认 360 秒（6 分钟）"),
 			),
+			mcp.WithBoolean("background",
+				mcp.DefaultBool(false),
+				mcp.Description("是否作为后台任务登录：立即返回任务 ID，用 job_status 查看是否登录成功、cancel_job 关闭浏览器，避免 MCP 客户端等待超时，默认 false"),
+			),
 		),
 		handleBrowserLogin,
 	)
@@ -61,6 +131,11 @@ func registerTools(s *server.MCPServer) {
 	//		mcp.WithString("output_file",
 	//			mcp.Description("输出文件路径（可选，默认 ~/.yst_go_mcp/output/new.md）"),
 	//		),
+	//		mcp.WithString("report_type",
+	//			mcp.DefaultString(collector.TypeDaily),
+	//			mcp.Enum(collector.ReportTypeNames()...),
+	//			mcp.Description("报告类型：daily 日报、weekly 周报、monthly 月报，默认 daily"),
+	//		),
 	//	),
 	//	handleCollectReports,
 	//)
@@ -75,24 +150,38 @@ func registerTools(s *server.MCPServer) {
 
 	// 4. auto_collect_reports 工具（自动化采集）
 	s.AddTool(
-		mcp.NewTool("auto_collect_reports",
-			mcp.WithDescription("自动采集日报数据（如果未登录会自动启动浏览器登录）"),
-			mcp.WithString("start_month",
-				mcp.Required(),
-				mcp.Description("起始月份，格式 YYYY-MM (例如: 2025-01)"),
-			),
-			mcp.WithString("end_month",
-				mcp.Required(),
-				mcp.Description("结束月份，格式 YYYY-MM (例如: 2025-03)"),
-			),
+		mcp.NewTool("auto_collect_reports", withDateRange(
+			mcp.WithDescription("自动采集日报/周报/月报数据（如果未登录会自动启动浏览器登录），支持按日期、周、季度或相对时间（如 上周、最近 30 天）采集"),
 			mcp.WithString("output_file",
-				mcp.Description("输出文件路径（可选，默认,mac是下载目录 ~/Downloads/x月日报.md，windows是保留桌面 C:\\Users\\用户名\\Desktop\\x月日报.md）"),
+				mcp.Description("输出文件路径（可选，默认输出目录下的 日报详情.md：macOS 为 ~/Downloads，Linux 为 XDG 下载目录，Windows 为桌面；相对路径也相对输出目录）"),
+			),
+			mcp.WithString("report_type",
+				mcp.DefaultString(collector.TypeDaily),
+				mcp.Enum(collector.ReportTypeNames()...),
+				mcp.Description("报告类型：daily 日报、weekly 周报、monthly 月报，默认 daily"),
 			),
 			mcp.WithNumber("login_timeout",
 				mcp.DefaultNumber(360),
 				mcp.Description("登录超时时间（秒），默认 360 秒（6 分钟）"),
 			),
-		),
+			mcp.WithBoolean("resume",
+				mcp.DefaultBool(false),
+				mcp.Description("是否从上次中断的检查点继续采集（相同报告类型和范围），默认 false"),
+			),
+			mcp.WithBoolean("partial",
+				mcp.DefaultBool(false),
+				mcp.Description("部分周期采集失败时是否仍导出已完成部分（文件开头会标注未完成的周期），默认 false"),
+			),
+			mcp.WithBoolean("download_assets",
+				mcp.DefaultBool(false),
+				mcp.Description("是否读取每份报告的详情页，下载附件和图片到 MD 文件同目录的 <文件名>_assets/ 并改为本地链接（按内容去重），默认 false"),
+			),
+			mcp.WithString("on_conflict",
+				mcp.DefaultString(fileutil.ConflictOverwrite),
+				mcp.Enum(fileutil.ConflictModes()...),
+				mcp.Description("输出文件已存在时：overwrite 覆盖（旧文件自动备份，可用 restore_export_version 恢复）、version 另存为新文件、fail 报错，默认 overwrite"),
+			),
+		)...),
 		handleAutoCollectReports,
 	)
 
@@ -107,6 +196,472 @@ func registerTools(s *server.MCPServer) {
 		),
 		handleGenerateSummaryCSV,
 	)
+
+	// 6. import_cookies 工具（从已有浏览器或 cookies.txt 导入登录态）
+	s.AddTool(
+		mcp.NewTool("import_cookies",
+			mcp.WithDescription("导入 Cookie（支持 Netscape cookies.txt、浏览器插件导出的 JSON、原始 Cookie 请求头），验证登录状态后保存"),
+			mcp.WithString("file_path",
+				mcp.Description("Cookie 文件路径（与 content 二选一）"),
+			),
+			mcp.WithString("content",
+				mcp.Description("Cookie 文本内容（与 file_path 二选一），例如 \"Cookie: a=1; b=2\""),
+			),
+			mcp.WithString("format",
+				mcp.DefaultString(cookie.FormatAuto),
+				mcp.Enum(cookie.FormatAuto, cookie.FormatNetscape, cookie.FormatJSON, cookie.FormatHeader),
+				mcp.Description("Cookie 格式，默认 auto 自动识别"),
+			),
+		),
+		handleImportCookies,
+	)
+
+	// 7. export_cookies 工具（导出登录态，便于迁移到其他机器）
+	s.AddTool(
+		mcp.NewTool("export_cookies",
+			mcp.WithDescription("导出已保存的 Cookie，可在其他机器上通过 import_cookies 导入"),
+			mcp.WithString("format",
+				mcp.DefaultString(cookie.FormatNetscape),
+				mcp.Enum(cookie.FormatNetscape, cookie.FormatJSON, cookie.FormatHeader),
+				mcp.Description("导出格式，默认 netscape（cookies.txt）"),
+			),
+			mcp.WithString("output_file",
+				mcp.Description("输出文件路径（可选，不填则直接返回内容）"),
+			),
+		),
+		handleExportCookies,
+	)
+
+	// 8. find_missing_reports 工具（按工作日历检测缺失/补交/重复日报）
+	s.AddTool(
+		mcp.NewTool("find_missing_reports", withDateRange(
+			mcp.WithDescription("对比工作日历（排除周末，支持法定节假日和调休上班日），检测指定日期范围内缺失、补交和重复的日报"),
+			mcp.WithString("holidays",
+				mcp.Description("额外的节假日，逗号分隔 YYYY-MM-DD 或 YYYY-MM-DD~YYYY-MM-DD（可选，内置中国大陆法定节假日）"),
+			),
+			mcp.WithString("workdays",
+				mcp.Description("额外的调休上班日，逗号分隔 YYYY-MM-DD（可选，内置中国大陆调休安排）"),
+			),
+		)...),
+		handleFindMissingReports,
+	)
+
+	// 9. report_stats 工具（日报统计分析）
+	s.AddTool(
+		mcp.NewTool("report_stats", withDateRange(
+			mcp.WithDescription("统计日报数据：每月日报数、相对工作日的提交率、平均字数、工时合计/平均、按星期分布和最长连续提交，同时返回表格和 JSON"),
+		)...),
+		handleReportStats,
+	)
+
+	// 10. extract_tasks 工具（提取关键词、项目和任务）
+	s.AddTool(
+		mcp.NewTool("extract_tasks", withDateRange(
+			mcp.WithDescription("从日报中提取高频关键词、项目和任务（支持中文分词、项目词典和工单号等正则标签），统计每个任务的涉及天数和首次/最近出现日期"),
+			mcp.WithString("md_file_path",
+				mcp.Description("已导出的日报 MD 文件路径（与日期范围二选一）"),
+			),
+			mcp.WithString("report_type",
+				mcp.DefaultString(collector.TypeDaily),
+				mcp.Enum(collector.ReportTypeNames()...),
+				mcp.Description("报告类型：daily 日报、weekly 周报、monthly 月报，默认 daily"),
+			),
+			mcp.WithNumber("top",
+				mcp.DefaultNumber(20),
+				mcp.Description("返回的任务和关键词数量，默认 20"),
+			),
+		)...),
+		handleExtractTasks,
+	)
+
+	// 11. submit_daily_report 工具（提交日报）
+	s.AddTool(
+		mcp.NewTool("submit_daily_report",
+			mcp.WithDescription("提交指定日期的日报（使用已保存的 Cookie，自动处理 CSRF Token，提交后重新读取列表验证），支持 dry_run 预览"),
+			mcp.WithString("content",
+				mcp.Required(),
+				mcp.Description("日报内容"),
+			),
+			mcp.WithString("date",
+				mcp.Description("日报日期，格式 YYYY-MM-DD（可选，默认今天）"),
+			),
+			mcp.WithBoolean("dry_run",
+				mcp.DefaultBool(false),
+				mcp.Description("只预览将要提交的表单，不实际提交"),
+			),
+			mcp.WithString("content_field",
+				mcp.Description("内容字段名（可选，默认自动识别）"),
+			),
+			mcp.WithString("date_field",
+				mcp.Description("日期字段名（可选，默认自动识别）"),
+			),
+		),
+		handleSubmitDailyReport,
+	)
+
+	// 12. update_daily_report 工具（修改日报）
+	s.AddTool(
+		mcp.NewTool("update_daily_report",
+			mcp.WithDescription("修改指定日期的日报内容，支持 dry_run 预览"),
+			mcp.WithString("content",
+				mcp.Required(),
+				mcp.Description("新的日报内容"),
+			),
+			mcp.WithString("date",
+				mcp.Description("日报日期，格式 YYYY-MM-DD（可选，默认今天）"),
+			),
+			mcp.WithString("report_url",
+				mcp.Description("日报链接（可选，不填则按日期在列表中查找）"),
+			),
+			mcp.WithBoolean("dry_run",
+				mcp.DefaultBool(false),
+				mcp.Description("只预览将要提交的表单，不实际提交"),
+			),
+			mcp.WithString("content_field",
+				mcp.Description("内容字段名（可选，默认自动识别）"),
+			),
+			mcp.WithString("date_field",
+				mcp.Description("日期字段名（可选，默认自动识别）"),
+			),
+		),
+		handleUpdateDailyReport,
+	)
+
+	// 13. draft_daily_report 工具（根据本地 git 提交生成日报草稿）
+	s.AddTool(
+		mcp.NewTool("draft_daily_report",
+			mcp.WithDescription("扫描本地 git 仓库中指定作者当天的提交，按仓库/分支分组，并合并上一个工作日日报中的明日计划，生成日报草稿（可交给 submit_daily_report 提交）"),
+			mcp.WithString("date",
+				mcp.Description("日报日期，格式 YYYY-MM-DD（可选，默认今天）"),
+			),
+			mcp.WithString("repos",
+				mcp.Description("git 仓库路径，逗号分隔（可选，默认读取配置目录 draft.json 的 repos）"),
+			),
+			mcp.WithString("author",
+				mcp.Description("提交作者（可选，默认 draft.json 的 author 或 git 全局 user.email）"),
+			),
+			mcp.WithBoolean("carry_plan",
+				mcp.DefaultBool(true),
+				mcp.Description("是否合并上一个工作日日报中的明日计划，默认 true（需要已登录）"),
+			),
+		),
+		handleDraftDailyReport,
+	)
+
+	// 14. collect_team_reports 工具（团队负责人采集下属报告）
+	s.AddTool(
+		mcp.NewTool("collect_team_reports", withDateRange(
+			mcp.WithDescription("团队负责人模式：采集下属成员指定日期范围的报告，按成员分节并附合并视图，日报会标记每个成员缺失的工作日"),
+			mcp.WithString("members",
+				mcp.Description("成员 ID 或姓名，逗号分隔（可选，默认全部下属）"),
+			),
+			mcp.WithString("output_file",
+				mcp.Description("输出文件路径（可选，默认输出目录下的 团队日报详情.md）"),
+			),
+			mcp.WithString("on_conflict",
+				mcp.DefaultString(fileutil.ConflictOverwrite),
+				mcp.Enum(fileutil.ConflictModes()...),
+				mcp.Description("输出文件已存在时：overwrite 覆盖（旧文件自动备份，可用 restore_export_version 恢复）、version 另存为新文件、fail 报错，默认 overwrite"),
+			),
+			mcp.WithString("report_type",
+				mcp.DefaultString(collector.TypeDaily),
+				mcp.Enum(collector.ReportTypeNames()...),
+				mcp.Description("报告类型：daily 日报、weekly 周报、monthly 月报，默认 daily"),
+			),
+		)...),
+		handleCollectTeamReports,
+	)
+
+	// 15. search_reports 工具（全文搜索已采集的报告）
+	s.AddTool(
+		mcp.NewTool("search_reports",
+			mcp.WithDescription("全文搜索已采集的报告（中文二元组 + 英文单词索引），支持 \"短语\" 查询和日期过滤，按相关度返回摘要和链接；采集工具会自动更新索引"),
+			mcp.WithString("query",
+				mcp.Required(),
+				mcp.Description("查询内容，多个关键词用空格分隔，短语用双引号，例如: \"支付网关\" 回调"),
+			),
+			mcp.WithString("start_date",
+				mcp.Description("起始日期 YYYY-MM-DD（可选）"),
+			),
+			mcp.WithString("end_date",
+				mcp.Description("结束日期 YYYY-MM-DD（可选）"),
+			),
+			mcp.WithNumber("limit",
+				mcp.DefaultNumber(20),
+				mcp.Description("返回结果数量，默认 20"),
+			),
+			mcp.WithString("index_start_month",
+				mcp.Description("搜索前先采集并索引的起始月份 YYYY-MM（可选，与 index_end_month 一起使用）"),
+			),
+			mcp.WithString("index_end_month",
+				mcp.Description("搜索前先采集并索引的结束月份 YYYY-MM（可选）"),
+			),
+		),
+		handleSearchReports,
+	)
+
+	// 16. start_collection 工具（后台采集，立即返回任务 ID）
+	s.AddTool(
+		mcp.NewTool("start_collection", withDateRange(
+			mcp.WithDescription("在后台启动采集任务（未登录时会自动启动浏览器登录），立即返回任务 ID，适合耗时较长的采集；用 job_status 查看进度，job_result 获取结果"),
+			mcp.WithString("output_file",
+				mcp.Description("输出文件路径（可选，同 auto_collect_reports）"),
+			),
+			mcp.WithString("report_type",
+				mcp.DefaultString(collector.TypeDaily),
+				mcp.Enum(collector.ReportTypeNames()...),
+				mcp.Description("报告类型：daily 日报、weekly 周报、monthly 月报，默认 daily"),
+			),
+			mcp.WithNumber("login_timeout",
+				mcp.DefaultNumber(360),
+				mcp.Description("登录超时时间（秒），默认 360 秒（6 分钟）"),
+			),
+			mcp.WithBoolean("resume",
+				mcp.DefaultBool(false),
+				mcp.Description("是否从上次中断的检查点继续采集（相同报告类型和范围），默认 false"),
+			),
+			mcp.WithBoolean("partial",
+				mcp.DefaultBool(false),
+				mcp.Description("部分周期采集失败时是否仍导出已完成部分（文件开头会标注未完成的周期），默认 false"),
+			),
+			mcp.WithBoolean("download_assets",
+				mcp.DefaultBool(false),
+				mcp.Description("是否读取每份报告的详情页，下载附件和图片到 MD 文件同目录的 <文件名>_assets/ 并改为本地链接（按内容去重），默认 false"),
+			),
+			mcp.WithString("on_conflict",
+				mcp.DefaultString(fileutil.ConflictOverwrite),
+				mcp.Enum(fileutil.ConflictModes()...),
+				mcp.Description("输出文件已存在时：overwrite 覆盖（旧文件自动备份，可用 restore_export_version 恢复）、version 另存为新文件、fail 报错，默认 overwrite"),
+			),
+		)...),
+		handleStartCollection,
+	)
+
+	// 17. job_status 工具
+	s.AddTool(
+		mcp.NewTool("job_status",
+			mcp.WithDescription("查看后台任务的状态、进度、已完成部分和错误信息"),
+			mcp.WithString("job_id",
+				mcp.Required(),
+				mcp.Description("任务 ID"),
+			),
+		),
+		handleJobStatus,
+	)
+
+	// 18. job_result 工具
+	s.AddTool(
+		mcp.NewTool("job_result",
+			mcp.WithDescription("获取后台任务的最终结果；任务未结束时返回当前进度和已完成部分"),
+			mcp.WithString("job_id",
+				mcp.Required(),
+				mcp.Description("任务 ID"),
+			),
+		),
+		handleJobResult,
+	)
+
+	// 19. list_jobs 工具
+	s.AddTool(
+		mcp.NewTool("list_jobs",
+			mcp.WithDescription("列出后台任务（最新的在前），服务重启前未完成的任务会标记为 interrupted"),
+			mcp.WithString("status",
+				mcp.Enum(jobs.StatusRunning, jobs.StatusSucceeded, jobs.StatusFailed, jobs.StatusCanceled, jobs.StatusInterrupted),
+				mcp.Description("按状态筛选（可选）"),
+			),
+			mcp.WithNumber("limit",
+				mcp.DefaultNumber(20),
+				mcp.Description("返回数量，默认 20"),
+			),
+		),
+		handleListJobs,
+	)
+
+	// 20. cancel_job 工具
+	s.AddTool(
+		mcp.NewTool("cancel_job",
+			mcp.WithDescription("取消运行中的后台任务"),
+			mcp.WithString("job_id",
+				mcp.Required(),
+				mcp.Description("任务 ID"),
+			),
+		),
+		handleCancelJob,
+	)
+
+	// 21. list_export_versions 工具
+	s.AddTool(
+		mcp.NewTool("list_export_versions",
+			mcp.WithDescription("列出导出文件被覆盖前自动备份的历史版本"),
+			mcp.WithString("file_path",
+				mcp.Required(),
+				mcp.Description("导出文件的完整路径"),
+			),
+		),
+		handleListExportVersions,
+	)
+
+	// 22. restore_export_version 工具
+	s.AddTool(
+		mcp.NewTool("restore_export_version",
+			mcp.WithDescription("用历史版本恢复导出文件，当前文件会先被备份"),
+			mcp.WithString("file_path",
+				mcp.Required(),
+				mcp.Description("导出文件的完整路径"),
+			),
+			mcp.WithString("version",
+				mcp.Required(),
+				mcp.Description("版本名（list_export_versions 返回的文件名，例如 20250310-150405.md）"),
+			),
+		),
+		handleRestoreExportVersion,
+	)
+
+	// 23. export_xlsx 工具
+	s.AddTool(
+		mcp.NewTool("export_xlsx", withExportSource(
+			mcp.WithDescription("导出 Excel 工作簿：每月一张汇总表（序号/主要工作任务/权重/任务成果情况，权重用公式计算、合计 100%），一张带原文链接的明细表，日报额外有统计表"),
+		)...),
+		handleExportXLSX,
+	)
+
+	// 24. export_docx 工具
+	s.AddTool(
+		mcp.NewTool("export_docx", withExportSource(
+			mcp.WithDescription("导出 Word 工作总结：标题、每月汇总表（序号/主要工作任务/权重/任务成果情况）、每个任务的工作说明，页眉页脚带姓名和周期；可套用带 {{title}} {{name}} {{period}} {{content}} 等占位符的模板"),
+			mcp.WithString("name",
+				mcp.Description("姓名，显示在副标题、页眉和页脚（可选）"),
+			),
+			mcp.WithString("template",
+				mcp.Description("Word 模板路径（可选，默认使用配置目录下的 summary_template.docx，不存在则使用内置样式）"),
+			),
+		)...),
+		handleExportDOCX,
+	)
+
+	// 25. export_html 工具
+	s.AddTool(
+		mcp.NewTool("export_html", withExportSource(
+			mcp.WithDescription("导出 HTML 工作总结：标题、每月汇总表、工作说明、提交统计和带原文链接的报告明细，样式内嵌，可直接在浏览器中查看或打印"),
+			mcp.WithString("name",
+				mcp.Description("姓名，显示在副标题（可选）"),
+			),
+		)...),
+		handleExportHTML,
+	)
+
+	// 26. export_pdf 工具
+	s.AddTool(
+		mcp.NewTool("export_pdf", withExportSource(
+			mcp.WithDescription("导出 PDF 工作总结：在无头 Chrome 中渲染 HTML 导出并打印，可设置纸张、方向、页边距和页眉页脚（需要本机安装 Chrome/Chromium）"),
+			mcp.WithString("name",
+				mcp.Description("姓名，显示在副标题和页脚（可选）"),
+			),
+			mcp.WithString("paper",
+				mcp.DefaultString(browser.DefaultPaper),
+				mcp.Enum(browser.PaperSizes()...),
+				mcp.Description("纸张大小，默认 A4"),
+			),
+			mcp.WithBoolean("landscape",
+				mcp.DefaultBool(false),
+				mcp.Description("是否横向打印，默认 false"),
+			),
+			mcp.WithString("margins",
+				mcp.DefaultString("20,15,20,15"),
+				mcp.Description("页边距（毫米），格式同 CSS：上,右,下,左 / 上下,左右 / 单个值，默认 20,15,20,15"),
+			),
+			mcp.WithBoolean("header_footer",
+				mcp.DefaultBool(true),
+				mcp.Description("是否打印页眉页脚（页脚右侧为页码），默认 true"),
+			),
+			mcp.WithString("header",
+				mcp.Description("页眉文字（可选，默认为文档标题）"),
+			),
+			mcp.WithString("footer",
+				mcp.Description("页脚文字（可选，默认为 姓名 + 周期）"),
+			),
+		)...),
+		handleExportPDF,
+	)
+
+	// 27. archive_report_pages 工具（后台存档报告页面截图和 MHTML 快照）
+	s.AddTool(
+		mcp.NewTool("archive_report_pages", withDateRange(
+			mcp.WithDescription("在后台用已保存的登录态逐个打开报告原文链接，保存整页 PNG 截图和 MHTML 快照到按时间命名的存档目录，并写出 manifest.json（链接、文件、SHA-256、抓取时间），作为已提交内容的留档；立即返回任务 ID"),
+			mcp.WithString("md_file_path",
+				mcp.Description("已导出的日报 MD 文件路径，使用其中的链接（与日期范围二选一）"),
+			),
+			mcp.WithString("report_type",
+				mcp.DefaultString(collector.TypeDaily),
+				mcp.Enum(collector.ReportTypeNames()...),
+				mcp.Description("报告类型：daily 日报、weekly 周报、monthly 月报，默认 daily"),
+			),
+			mcp.WithString("output_dir",
+				mcp.Description("存档根目录（可选，默认数据目录下的 archives），本次存档保存在其下的 <时间戳> 子目录"),
+			),
+			mcp.WithNumber("page_timeout",
+				mcp.DefaultNumber(60),
+				mcp.Description("单个页面的抓取超时（秒），默认 60"),
+			),
+			mcp.WithNumber("login_timeout",
+				mcp.DefaultNumber(360),
+				mcp.Description("登录超时时间（秒），默认 360 秒（6 分钟）"),
+			),
+		)...),
+		handleArchiveReportPages,
+	)
+
+	// 28. view_report_page 工具（返回报告页面截图和页面文本）
+	s.AddTool(
+		mcp.NewTool("view_report_page",
+			mcp.WithDescription("用已保存的登录态在无头浏览器中打开报告页面，返回整页截图（图片）和页面文本，用于查看表格、图片、格式等文本采集中丢失的内容"),
+			mcp.WithString("link",
+				mcp.Description("报告链接（完整地址或列表页中的相对链接，与 date 二选一）"),
+			),
+			mcp.WithString("date",
+				mcp.Description("报告日期，例如 2025-03-10、昨天；周报/月报可用周或月份，例如 2025-W11、2025-03（与 link 二选一）"),
+			),
+			mcp.WithString("report_type",
+				mcp.DefaultString(collector.TypeDaily),
+				mcp.Enum(collector.ReportTypeNames()...),
+				mcp.Description("按 date 查找时的报告类型：daily 日报、weekly 周报、monthly 月报，默认 daily"),
+			),
+			mcp.WithNumber("quality",
+				mcp.DefaultNumber(80),
+				mcp.Description("截图质量 1-100，100 为 PNG，其余为 JPEG（体积更小），默认 80"),
+			),
+			mcp.WithNumber("max_height",
+				mcp.DefaultNumber(viewPageMaxHeight),
+				mcp.Description(fmt.Sprintf("截图最大高度（像素），页面更高时只截取顶部，完整内容见返回的文本，默认 %d", viewPageMaxHeight)),
+			),
+			mcp.WithNumber("timeout",
+				mcp.DefaultNumber(60),
+				mcp.Description("页面加载超时（秒），默认 60"),
+			),
+		),
+		handleViewReportPage,
+	)
+
+	// 29. export_ics 工具
+	s.AddTool(
+		mcp.NewTool("export_ics", withExportSource(
+			mcp.WithDescription("导出 iCalendar（.ics）日历：每份报告一个全天事件（标题为报告第一行，描述为正文，附原文链接），可选为明日计划条目在下一个工作日生成事件，可导入系统日历、Outlook、Google 日历等"),
+			mcp.WithString("name",
+				mcp.Description("日历名称（可选，默认为 报告类型（周期））"),
+			),
+			mcp.WithBoolean("plan_events",
+				mcp.DefaultBool(false),
+				mcp.Description("是否为日报中的明日计划条目生成事件（放在报告之后的下一个工作日），默认 false"),
+			),
+			mcp.WithString("holidays",
+				mcp.Description("额外的节假日，用于计算明日计划的日期（可选）：逗号分隔，支持范围，例如 2025-04-04,2025-05-01~2025-05-05"),
+			),
+			mcp.WithString("workdays",
+				mcp.Description("额外的调休上班日（可选），格式同 holidays"),
+			),
+		)...),
+		handleExportICS,
+	)
 }
 
 // handleBrowserLogin 处理浏览器登录
@@ -116,7 +671,28 @@ func handleBrowserLogin(arguments map[string]interface{}) (*mcp.CallToolResult,
 		timeout = int(val)
 	}
 
-	log.Printf("browser_login 工具被调用，timeout=%d", timeout)
+	background, _ := arguments["background"].(bool)
+
+	log.Printf("browser_login 工具被调用，timeout=%d, background=%v", timeout, background)
+
+	if background {
+		if jobManager == nil {
+			return mcp.NewToolResultError("后台任务不可用，请检查状态目录是否可写"), nil
+		}
+		params := map[string]string{"timeout": fmt.Sprint(timeout)}
+		job, err := jobManager.Start("login", params, func(ctx context.Context, p *jobs.Progress) (string, error) {
+			p.Stage("等待在浏览器中登录")
+			if err := browser.NewLogin().LaunchBrowserLogin(ctx, timeout); err != nil {
+				return "", err
+			}
+			p.Stage("已完成")
+			return "✅ 登录成功！Cookie 已保存", nil
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("启动任务失败: %v", err)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("🚀 已在后台启动登录任务 %s，请在弹出的浏览器中完成登录（%d 秒内）\n\n使用 job_status 查看是否登录成功，cancel_job 取消并关闭浏览器", job.ID, timeout)), nil
+	}
 
 	loginManager := browser.NewLogin()
 	if err := loginManager.LaunchBrowserLogin(context.Background(), timeout); err != nil {
@@ -128,25 +704,25 @@ func handleBrowserLogin(arguments map[string]interface{}) (*mcp.CallToolResult,
 
 // handleCollectReports 处理日报采集
 func handleCollectReports(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-	startMonth, ok := arguments["start_month"].(string)
-	if !ok || startMonth == "" {
-		return mcp.NewToolResultError("start_month 参数必须提供"), nil
-	}
-
-	endMonth, ok := arguments["end_month"].(string)
-	if !ok || endMonth == "" {
-		return mcp.NewToolResultError("end_month 参数必须提供"), nil
+	r, errResult := parseDateRange(arguments)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	outputFile, _ := arguments["output_file"].(string)
+	reportType, _ := arguments["report_type"].(string)
 
-	log.Printf("collect_reports 工具被调用: %s 到 %s, 输出: %s", startMonth, endMonth, outputFile)
+	log.Printf("collect_reports 工具被调用: %s, 类型: %s, 输出: %s", r, reportType, outputFile)
 
-	c := collector.NewCollector()
-	result, err := c.Collect(startMonth, endMonth, outputFile)
+	c, err := newCollector(reportType)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	result, err := c.Collect(r, outputFile)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("采集失败: %v", err)), nil
 	}
+	indexReports(c.ReportType().Name, c.Collected())
 
 	return mcp.NewToolResultText(result), nil
 }
@@ -166,28 +742,60 @@ func handleClearCookies(arguments map[string]interface{}) (*mcp.CallToolResult,
 // handleAutoCollectReports 处理自动采集（自动登录+采集）
 func handleAutoCollectReports(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
 	// 解析参数
-	startMonth, ok := arguments["start_month"].(string)
-	if !ok || startMonth == "" {
-		return mcp.NewToolResultError("start_month 参数必须提供"), nil
-	}
-
-	endMonth, ok := arguments["end_month"].(string)
-	if !ok || endMonth == "" {
-		return mcp.NewToolResultError("end_month 参数必须提供"), nil
+	r, errResult := parseDateRange(arguments)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	outputFile, _ := arguments["output_file"].(string)
+	reportType, _ := arguments["report_type"].(string)
 
 	loginTimeout := 360
 	if val, ok := arguments["login_timeout"].(float64); ok {
 		loginTimeout = int(val)
 	}
 
-	log.Printf("auto_collect_reports 工具被调用: %s 到 %s, 超时: %d 秒", startMonth, endMonth, loginTimeout)
+	log.Printf("auto_collect_reports 工具被调用: %s, 超时: %d 秒", r, loginTimeout)
+
+	c, err := loginCollector(context.Background(), reportType, loginTimeout)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := setCollectOptions(c, arguments); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// 开始采集数据
+	log.Printf("📊 开始采集%s数据: %s", c.ReportType().Label, r)
+	result, err := c.Collect(r, outputFile)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("采集失败: %v", err)), nil
+	}
+	indexReports(c.ReportType().Name, c.Collected())
+
+	return mcp.NewToolResultText(result), nil
+}
+
+// setCollectOptions 应用 resume / partial / download_assets / on_conflict 参数
+func setCollectOptions(c *collector.Collector, arguments map[string]interface{}) error {
+	resume, _ := arguments["resume"].(bool)
+	partial, _ := arguments["partial"].(bool)
+	downloadAssets, _ := arguments["download_assets"].(bool)
+	onConflict, _ := arguments["on_conflict"].(string)
+	c.SetResume(resume)
+	c.SetAllowPartial(partial)
+	c.SetDownloadAssets(downloadAssets)
+	return c.SetOnConflict(onConflict)
+}
 
+// loginCollector 创建采集器并确保已登录：Cookie 无效时启动浏览器登录并等待完成
+func loginCollector(ctx context.Context, reportType string, loginTimeout int) (*collector.Collector, error) {
 	// 创建 cookie 管理器
 	cookieManager := cookie.NewManager()
-	c := collector.NewCollector()
+	c, err := newCollector(reportType)
+	if err != nil {
+		return nil, err
+	}
 
 	// 检查 cookie 是否存在且有效
 	needLogin := false
@@ -215,7 +823,7 @@ func handleAutoCollectReports(arguments map[string]interface{}) (*mcp.CallToolRe
 		// 启动浏览器登录（异步）
 		go func() {
 			loginManager := browser.NewLogin()
-			err := loginManager.LaunchBrowserLogin(context.Background(), loginTimeout)
+			err := loginManager.LaunchBrowserLogin(ctx, loginTimeout)
 			loginResult <- err
 		}()
 
@@ -228,9 +836,12 @@ func handleAutoCollectReports(arguments map[string]interface{}) (*mcp.CallToolRe
 
 		for {
 			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("登录已取消: %w", ctx.Err())
+
 			case err := <-loginResult:
 				if err != nil {
-					return mcp.NewToolResultError(fmt.Sprintf("登录失败: %v", err)), nil
+					return nil, fmt.Errorf("登录失败: %w", err)
 				}
 				log.Println("✓ 登录成功！")
 				goto LOGIN_SUCCESS
@@ -241,11 +852,11 @@ func handleAutoCollectReports(arguments map[string]interface{}) (*mcp.CallToolRe
 					log.Println("✓ 检测到 Cookie 文件已创建")
 
 					// 尝试验证登录状态
-					newCollector := collector.NewCollector()
-					if err := newCollector.LoadSavedCookies(); err == nil {
-						if newCollector.CheckLoginStatus() {
+					loggedIn, _ := newCollector(reportType)
+					if err := loggedIn.LoadSavedCookies(); err == nil {
+						if loggedIn.CheckLoginStatus() {
 							log.Println("✓ 登录状态验证成功！")
-							c = newCollector
+							c = loggedIn
 							goto LOGIN_SUCCESS
 						}
 					}
@@ -254,7 +865,7 @@ func handleAutoCollectReports(arguments map[string]interface{}) (*mcp.CallToolRe
 
 				// 检查超时
 				if time.Now().After(deadline) {
-					return mcp.NewToolResultError(fmt.Sprintf("登录超时（%d 秒）", loginTimeout)), nil
+					return nil, fmt.Errorf("登录超时（%d 秒）", loginTimeout)
 				}
 
 				elapsed := int(time.Since(deadline.Add(-time.Duration(loginTimeout) * time.Second)).Seconds())
@@ -267,20 +878,14 @@ func handleAutoCollectReports(arguments map[string]interface{}) (*mcp.CallToolRe
 
 		// 如果之前没有加载过 cookie，现在加载
 		if err := c.LoadSavedCookies(); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("加载 Cookie 失败: %v", err)), nil
+			return nil, fmt.Errorf("加载 Cookie 失败: %w", err)
 		}
 	} else {
 		log.Println("✓ Cookie 有效，跳过登录")
 	}
 
-	// 开始采集数据
-	log.Printf("📊 开始采集日报数据: %s 到 %s", startMonth, endMonth)
-	result, err := c.Collect(startMonth, endMonth, outputFile)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("采集失败: %v", err)), nil
-	}
-
-	return mcp.NewToolResultText(result), nil
+	c.SetContext(ctx)
+	return c, nil
 }
 
 // handleGenerateSummaryCSV 处理读取日报 MD 并输出内容供 AI 整理
@@ -298,6 +903,22 @@ func handleGenerateSummaryCSV(arguments map[string]interface{}) (*mcp.CallToolRe
 		return mcp.NewToolResultError(fmt.Sprintf("读取 MD 文件失败: %v", err)), nil
 	}
 
+	// 预先提取任务并按涉及天数计算建议权重，减少 AI 估算偏差
+	weightHint := ""
+	if extractor, err := loadExtractor(); err != nil {
+		log.Printf("加载项目词典失败: %v", err)
+	} else {
+		extraction := extractor.Extract(collector.ParseMarkdownReports(content), summaryTaskLimit)
+		weights := analysis.TaskWeights(extraction.Tasks)
+		var b strings.Builder
+		for i, t := range extraction.Tasks {
+			fmt.Fprintf(&b, "- %s：涉及 %d 天（%s ~ %s），建议权重 %d%%\n", t.Name, t.Days, t.FirstSeen, t.LastSeen, weights[i])
+		}
+		if b.Len() > 0 {
+			weightHint = "\n程序预提取的任务及建议权重（按涉及天数计算，总和 100%，可合并相近任务后按比例调整）：\n" + b.String()
+		}
+	}
+
 	result := fmt.Sprintf(`📄 已读取日报详情文件: %s
 
 请根据以下日报内容，整理生成 CSV 格式的月度汇总表格，包含以下列：
@@ -313,7 +934,1436 @@ func handleGenerateSummaryCSV(arguments map[string]interface{}) (*mcp.CallToolRe
 %s
 ---
 
-请分析日报内容，提取主要工作任务，并生成符合格式的 CSV 文件。`, mdFilePath, csvPath, content)
+请分析日报内容，提取主要工作任务，并生成符合格式的 CSV 文件。%s`, mdFilePath, csvPath, content, weightHint)
 
 	return mcp.NewToolResultText(result), nil
 }
+
+// handleImportCookies 处理导入 Cookie
+func handleImportCookies(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	filePath, _ := arguments["file_path"].(string)
+	content, _ := arguments["content"].(string)
+	format, _ := arguments["format"].(string)
+
+	if filePath == "" && content == "" {
+		return mcp.NewToolResultError("file_path 或 content 参数必须提供一个"), nil
+	}
+
+	log.Printf("import_cookies 工具被调用: file=%s, format=%s", filePath, format)
+
+	if filePath != "" {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("读取 Cookie 文件失败: %v", err)), nil
+		}
+		content = string(data)
+	}
+
+	cookies, err := cookie.ParseCookies(content, format, collector.SiteDomain)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("解析 Cookie 失败: %v", err)), nil
+	}
+
+	// 先验证再保存，避免无效 Cookie 覆盖现有登录态
+	c := collector.NewCollector()
+	c.ApplyCookies(cookies)
+	if !c.CheckLoginStatus() {
+		return mcp.NewToolResultError(fmt.Sprintf("导入的 %d 个 Cookie 无法登录（可能已过期），未保存", len(cookies))), nil
+	}
+
+	manager := cookie.NewManager()
+	if err := manager.SaveCookies(cookies); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("保存 Cookie 失败: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("✅ 已导入 %d 个 Cookie，登录状态有效，已保存到 %s", len(cookies), manager.GetCookieFile())), nil
+}
+
+// handleExportCookies 处理导出 Cookie
+func handleExportCookies(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	format, _ := arguments["format"].(string)
+	if format == "" {
+		format = cookie.FormatNetscape
+	}
+	outputFile, _ := arguments["output_file"].(string)
+
+	log.Printf("export_cookies 工具被调用: format=%s, output=%s", format, outputFile)
+
+	manager := cookie.NewManager()
+	cookies, err := manager.LoadCookies()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("加载 Cookie 失败: %v", err)), nil
+	}
+	if len(cookies) == 0 {
+		return mcp.NewToolResultError("没有保存的 Cookie，请先登录或导入"), nil
+	}
+
+	content, err := cookie.FormatCookies(cookies, format)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("导出失败: %v", err)), nil
+	}
+
+	if outputFile == "" {
+		return mcp.NewToolResultText(content), nil
+	}
+
+	// Cookie 属于敏感数据，仅当前用户可读
+	if err := os.WriteFile(outputFile, []byte(content), 0600); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("写入文件失败: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("✅ 已导出 %d 个 Cookie 到 %s（%s 格式），请妥善保管", len(cookies), outputFile, format)), nil
+}
+
+// handleFindMissingReports 处理日报缺失检测
+func handleFindMissingReports(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	r, errResult := parseDateRange(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	holidays, _ := arguments["holidays"].(string)
+	workdays, _ := arguments["workdays"].(string)
+
+	log.Printf("find_missing_reports 工具被调用: %s", r)
+
+	cal, err := loadCalendar(holidays, workdays)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("加载工作日历失败: %v", err)), nil
+	}
+
+	end := r.End
+	if cutoff := reportCutoff(end.Location()); end.After(cutoff) {
+		end = cutoff
+	}
+
+	// 部分月份失败时仍检测其余月份，失败的月份不计为缺失
+	c := collector.NewCollector()
+	_, allReports, err := c.CollectRange(r)
+	var fetchErr *collector.FetchError
+	if err != nil && !errors.As(err, &fetchErr) {
+		return mcp.NewToolResultError(fmt.Sprintf("采集失败: %v", err)), nil
+	}
+
+	var reports []collector.Report
+	for _, items := range allReports {
+		reports = append(reports, items...)
+	}
+
+	result := analysis.FindGaps(reports, cal, r.Start, end)
+	if fetchErr != nil {
+		result.ExcludeFailed(fetchErr.Failed)
+	}
+	return mcp.NewToolResultText(result.Format()), nil
+}
+
+// handleReportStats 处理日报统计
+func handleReportStats(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	r, errResult := parseDateRange(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	log.Printf("report_stats 工具被调用: %s", r)
+
+	cal, err := loadCalendar("", "")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("加载工作日历失败: %v", err)), nil
+	}
+
+	c := collector.NewCollector()
+	months, allReports, err := c.CollectRange(r)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("采集失败: %v", err)), nil
+	}
+
+	end := r.End
+	if cutoff := reportCutoff(end.Location()); end.After(cutoff) {
+		end = cutoff
+	}
+	stats, err := analysis.ComputeStats(months, allReports, cal, r.Start, end)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("统计失败: %v", err)), nil
+	}
+
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("序列化统计结果失败: %v", err)), nil
+	}
+
+	result := fmt.Sprintf("📊 日报统计（%s）\n\n%s\n```json\n%s\n```", r, stats.Table(), data)
+	return mcp.NewToolResultText(result), nil
+}
+
+// reportCutoff 返回参与统计的最后一天：今天的日报可能还没写，只统计到昨天。
+// “昨天”按 loc（日期范围所在的时区，即 timezone 参数或 YST_TIMEZONE）计算
+func reportCutoff(loc *time.Location) time.Time {
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, loc)
+}
+
+// loadCalendar 加载工作日历：内置节假日 + 配置目录用户配置 + 调用参数中的额外日期
+func loadCalendar(holidays, workdays string) (*calendar.Calendar, error) {
+	cal, err := calendar.Load(paths.Config())
+	if err != nil {
+		return nil, err
+	}
+	if err := cal.AddHolidays(calendar.SplitDates(holidays)...); err != nil {
+		return nil, err
+	}
+	if err := cal.AddWorkdays(calendar.SplitDates(workdays)...); err != nil {
+		return nil, err
+	}
+	return cal, nil
+}
+
+// handleExtractTasks 处理关键词和任务提取
+func handleExtractTasks(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	mdFilePath, _ := arguments["md_file_path"].(string)
+	reportType, _ := arguments["report_type"].(string)
+
+	top := 20
+	if val, ok := arguments["top"].(float64); ok && val > 0 {
+		top = int(val)
+	}
+
+	log.Printf("extract_tasks 工具被调用: md=%s", mdFilePath)
+
+	var reports []collector.Report
+	source := mdFilePath
+	if mdFilePath != "" {
+		content, err := os.ReadFile(mdFilePath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("读取 MD 文件失败: %v", err)), nil
+		}
+		reports = collector.ParseMarkdownReports(string(content))
+	} else {
+		r, errResult := parseDateRange(arguments)
+		if errResult != nil {
+			return errResult, nil
+		}
+		c, err := newCollector(reportType)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		_, allReports, err := c.CollectRange(r)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("采集失败: %v", err)), nil
+		}
+		for _, items := range allReports {
+			reports = append(reports, items...)
+		}
+		source = "范围 " + r.String()
+	}
+
+	extractor, err := loadExtractor()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("加载项目词典失败: %v", err)), nil
+	}
+
+	extraction := extractor.Extract(reports, top)
+	data, err := json.MarshalIndent(extraction, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("序列化结果失败: %v", err)), nil
+	}
+
+	result := fmt.Sprintf("🔍 从 %d 份日报（%s）中提取到 %d 个任务\n\n%s\n```json\n%s\n```", len(reports), source, len(extraction.Tasks), extraction.Format(), data)
+	return mcp.NewToolResultText(result), nil
+}
+
+// loadExtractor 使用配置目录 keywords.json 中的项目词典创建提取器
+func loadExtractor() (*analysis.Extractor, error) {
+	dict, err := analysis.LoadDictionary(filepath.Join(paths.Config(), analysis.KeywordsFileName))
+	if err != nil {
+		return nil, err
+	}
+	return analysis.NewExtractor(dict)
+}
+
+// handleSubmitDailyReport 处理提交日报
+func handleSubmitDailyReport(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	req, errResult := parseSubmitRequest(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	log.Printf("submit_daily_report 工具被调用: %s, dry_run=%v", req.Date.Format("2006-01-02"), req.DryRun)
+
+	c := collector.NewCollector()
+	result, err := c.SubmitDailyReport(req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("提交日报失败: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(formatSubmitResult(result, req)), nil
+}
+
+// handleUpdateDailyReport 处理修改日报
+func handleUpdateDailyReport(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	req, errResult := parseSubmitRequest(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+	req.ReportURL, _ = arguments["report_url"].(string)
+
+	log.Printf("update_daily_report 工具被调用: %s, dry_run=%v", req.Date.Format("2006-01-02"), req.DryRun)
+
+	c := collector.NewCollector()
+	result, err := c.UpdateDailyReport(req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("修改日报失败: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(formatSubmitResult(result, req)), nil
+}
+
+// parseSubmitRequest 解析提交/修改日报的公共参数
+func parseSubmitRequest(arguments map[string]interface{}) (collector.SubmitRequest, *mcp.CallToolResult) {
+	var req collector.SubmitRequest
+
+	content, ok := arguments["content"].(string)
+	if !ok || strings.TrimSpace(content) == "" {
+		return req, mcp.NewToolResultError("content 参数必须提供")
+	}
+	req.Content = content
+
+	req.Date = time.Now()
+	if date, _ := arguments["date"].(string); date != "" {
+		t, err := daterange.ParseDay(date, time.Now())
+		if err != nil {
+			return req, mcp.NewToolResultError(fmt.Sprintf("日期错误: %v", err))
+		}
+		req.Date = t
+	}
+
+	req.DryRun, _ = arguments["dry_run"].(bool)
+	req.ContentField, _ = arguments["content_field"].(string)
+	req.DateField, _ = arguments["date_field"].(string)
+
+	return req, nil
+}
+
+// formatSubmitResult 生成提交/修改日报的结果说明
+func formatSubmitResult(result *collector.SubmitResult, req collector.SubmitRequest) string {
+	date := req.Date.Format("2006-01-02")
+	action := "提交"
+	if result.Action == "update" {
+		action = "修改"
+	}
+
+	if req.DryRun {
+		return fmt.Sprintf("📝 预览（未%s）%s 的日报：\n\n%s", action, date, result.Preview())
+	}
+
+	if result.Report == nil {
+		return fmt.Sprintf("⚠️ %s 的日报已%s，但在日报列表中未找到，请到网页确认", date, action)
+	}
+	if !result.Verified {
+		return fmt.Sprintf("⚠️ %s 的日报已%s，但读取到的内容与提交的内容不一致，请到网页确认：%s", date, action, result.Report.Link)
+	}
+
+	return fmt.Sprintf("✅ %s 的日报已%s并验证成功：%s", date, action, result.Report.Link)
+}
+
+// handleDraftDailyReport 处理生成日报草稿
+func handleDraftDailyReport(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	day := time.Now()
+	if date, _ := arguments["date"].(string); date != "" {
+		t, err := daterange.ParseDay(date, time.Now())
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("日期错误: %v", err)), nil
+		}
+		day = t
+	}
+
+	carryPlan := true
+	if val, ok := arguments["carry_plan"].(bool); ok {
+		carryPlan = val
+	}
+
+	cfg, err := draft.LoadConfig(filepath.Join(paths.Config(), draft.ConfigFileName))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	repos := cfg.Repos
+	if val, _ := arguments["repos"].(string); val != "" {
+		repos = nil
+		for _, repo := range strings.Split(val, ",") {
+			if repo = strings.TrimSpace(repo); repo != "" {
+				repos = append(repos, repo)
+			}
+		}
+	}
+	if len(repos) == 0 {
+		return mcp.NewToolResultError("未配置 git 仓库，请传入 repos 参数或在配置目录 draft.json 中配置 repos"), nil
+	}
+
+	author, _ := arguments["author"].(string)
+	if author == "" {
+		author = cfg.Author
+	}
+	if author == "" {
+		author = draft.DefaultAuthor()
+	}
+
+	log.Printf("draft_daily_report 工具被调用: %s, 仓库 %d 个, 作者 %s", day.Format("2006-01-02"), len(repos), author)
+
+	repoCommits, errs := draft.ScanRepos(repos, author, day)
+	if len(errs) == len(repos) {
+		return mcp.NewToolResultError(fmt.Sprintf("扫描 git 提交失败: %v", errors.Join(errs...))), nil
+	}
+
+	var notes []string
+	for _, err := range errs {
+		log.Printf("⚠ 已跳过: %v", err)
+		notes = append(notes, fmt.Sprintf("已跳过: %v", err))
+	}
+	var carried []string
+	if carryPlan {
+		plan, note := previousPlan(day)
+		carried = plan
+		if note != "" {
+			notes = append(notes, note)
+		}
+	}
+
+	commitCount := 0
+	for _, rc := range repoCommits {
+		for _, commits := range rc.Branches {
+			commitCount += len(commits)
+		}
+	}
+
+	result := fmt.Sprintf("📝 %s 日报草稿（%d 个仓库，%d 次提交，%d 条昨日计划）\n\n%s",
+		day.Format("2006-01-02"), len(repoCommits), commitCount, len(carried), draft.Build(repoCommits, carried))
+	if len(notes) > 0 {
+		result += "\n⚠️ " + strings.Join(notes, "\n⚠️ ")
+	}
+	result += "\n\n确认无误后，可将草稿内容传给 submit_daily_report 提交。"
+
+	return mcp.NewToolResultText(result), nil
+}
+
+// previousPlan 读取上一个工作日日报中的明日计划，失败时返回说明而不中断草稿生成
+func previousPlan(day time.Time) ([]string, string) {
+	cal, err := loadCalendar("", "")
+	if err != nil {
+		return nil, fmt.Sprintf("加载工作日历失败，未合并昨日计划: %v", err)
+	}
+	prev := cal.PrevWorkday(day)
+
+	c := collector.NewCollector()
+	if err := c.EnsureLogin(); err != nil {
+		return nil, fmt.Sprintf("未合并昨日计划: %v", err)
+	}
+
+	report, err := c.FindReportByDate(prev)
+	if err != nil {
+		return nil, fmt.Sprintf("未合并昨日计划: %v", err)
+	}
+	if report == nil {
+		return nil, fmt.Sprintf("%s 没有日报，未合并昨日计划", prev.Format("2006-01-02"))
+	}
+
+	return draft.ExtractPlan(report.Text), ""
+}
+
+// newCollector 创建指定报告类型的采集器
+func newCollector(reportType string) (*collector.Collector, error) {
+	c := collector.NewCollector()
+	if err := c.SetReportType(reportType); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// withDateRange 为工具追加日期范围参数：range 或 start_month/end_month，以及时区
+func withDateRange(opts ...mcp.ToolOption) []mcp.ToolOption {
+	return append(opts,
+		mcp.WithString("range",
+			mcp.Description("日期范围（与 start_month/end_month 二选一），例如 2025-03-10~2025-03-21、2025-W11、2025-Q1、今天、本周、上周、上个月、本季度、最近 30 天、last 30 days"),
+		),
+		mcp.WithString("start_month",
+			mcp.Description("起始月份 YYYY-MM，也可以是日期、ISO 周、季度或相对表达（例如: 2025-01、2025-03-10、上个月）"),
+		),
+		mcp.WithString("end_month",
+			mcp.Description("结束月份 YYYY-MM，也可以是日期、ISO 周、季度或相对表达（可选，默认与起始相同）"),
+		),
+		mcp.WithString("timezone",
+			mcp.Description("解析日期使用的时区，例如 Asia/Shanghai（可选，默认环境变量 YST_TIMEZONE 或系统时区）"),
+		),
+	)
+}
+
+// parseDateRange 解析 range 或 start_month/end_month 参数
+func parseDateRange(arguments map[string]interface{}) (daterange.Range, *mcp.CallToolResult) {
+	expr, _ := arguments["range"].(string)
+	startMonth, _ := arguments["start_month"].(string)
+	endMonth, _ := arguments["end_month"].(string)
+	timezone, _ := arguments["timezone"].(string)
+
+	loc, err := daterange.Location(timezone)
+	if err != nil {
+		return daterange.Range{}, mcp.NewToolResultError(err.Error())
+	}
+	now := time.Now().In(loc)
+
+	var r daterange.Range
+	switch {
+	case expr != "":
+		r, err = daterange.Parse(expr, now)
+	case startMonth != "":
+		r, err = daterange.Between(startMonth, endMonth, now)
+	default:
+		return daterange.Range{}, mcp.NewToolResultError("需要提供 range，或 start_month 和 end_month 参数")
+	}
+	if err != nil {
+		return daterange.Range{}, mcp.NewToolResultError(fmt.Sprintf("日期范围错误: %v", err))
+	}
+	return r, nil
+}
+
+// handleCollectTeamReports 处理团队报告采集
+func handleCollectTeamReports(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	r, errResult := parseDateRange(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	outputFile, _ := arguments["output_file"].(string)
+	reportType, _ := arguments["report_type"].(string)
+
+	var names []string
+	if val, _ := arguments["members"].(string); val != "" {
+		for _, name := range strings.Split(val, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	log.Printf("collect_team_reports 工具被调用: %s, 成员: %v", r, names)
+
+	c, err := newCollector(reportType)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := setCollectOptions(c, arguments); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	keys, team, err := c.CollectTeamReports(r, names)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("采集失败: %v", err)), nil
+	}
+
+	// 日报按工作日历标记缺失天数
+	flags := make(map[string]string)
+	var lines []string
+	var cal *calendar.Calendar
+	end := r.End
+	if c.ReportType().Name == collector.TypeDaily {
+		if cal, err = loadCalendar("", ""); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("加载工作日历失败: %v", err)), nil
+		}
+		if cutoff := reportCutoff(end.Location()); end.After(cutoff) {
+			end = cutoff
+		}
+	}
+
+	for _, mr := range team {
+		all := mr.All(keys)
+		line := fmt.Sprintf("- %s：%d 条", mr.Member.Name, len(all))
+		// 采集失败的周期单独标记，不计入缺失
+		var notes []string
+		if len(mr.Failed) > 0 {
+			notes = append(notes, fmt.Sprintf("%s 采集失败", strings.Join(mr.Failed, "、")))
+		}
+		if cal != nil {
+			gaps := analysis.FindGaps(all, cal, r.Start, end)
+			gaps.ExcludeFailed(mr.Failed)
+			if len(gaps.Missing) > 0 {
+				var days []string
+				for _, d := range gaps.Missing {
+					days = append(days, d.Format("01-02"))
+				}
+				notes = append(notes, fmt.Sprintf("缺失 %d 个工作日：%s", len(gaps.Missing), strings.Join(days, "、")))
+			} else {
+				line += "，无缺失"
+			}
+		}
+		if len(notes) > 0 {
+			flags[mr.Member.ID] = strings.Join(notes, "；")
+			line += "，" + flags[mr.Member.ID]
+		}
+		lines = append(lines, line)
+	}
+
+	path, err := c.WriteTeamMarkdown(keys, team, flags, outputFile)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("生成 Markdown 失败: %v", err)), nil
+	}
+
+	result := fmt.Sprintf("✓ 团队%s采集完成！范围 %s，共 %d 名成员，已保存到 %s\n\n%s",
+		c.ReportType().Label, r, len(team), path, strings.Join(lines, "\n"))
+	return mcp.NewToolResultText(result), nil
+}
+
+// handleSearchReports 处理全文搜索
+func handleSearchReports(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	query, ok := arguments["query"].(string)
+	if !ok || strings.TrimSpace(query) == "" {
+		return mcp.NewToolResultError("query 参数必须提供"), nil
+	}
+
+	q := search.Query{Text: query, Limit: 20}
+	if date, _ := arguments["start_date"].(string); date != "" {
+		r, err := daterange.Parse(date, time.Now())
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("start_date 错误: %v", err)), nil
+		}
+		q.StartDate = r.Start.Format(daterange.DateLayout)
+	}
+	if date, _ := arguments["end_date"].(string); date != "" {
+		r, err := daterange.Parse(date, time.Now())
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("end_date 错误: %v", err)), nil
+		}
+		q.EndDate = r.End.Format(daterange.DateLayout)
+	}
+	if val, ok := arguments["limit"].(float64); ok && val > 0 {
+		q.Limit = int(val)
+	}
+
+	log.Printf("search_reports 工具被调用: %q, %s ~ %s", query, q.StartDate, q.EndDate)
+
+	indexStart, _ := arguments["index_start_month"].(string)
+	indexEnd, _ := arguments["index_end_month"].(string)
+	if indexStart != "" && indexEnd != "" {
+		r, err := daterange.Between(indexStart, indexEnd, time.Now())
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("索引范围错误: %v", err)), nil
+		}
+		c := collector.NewCollector()
+		months, allReports, err := c.CollectRange(r)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("采集失败: %v", err)), nil
+		}
+		var reports []collector.Report
+		for _, month := range months {
+			reports = append(reports, allReports[month]...)
+		}
+		indexReports(c.ReportType().Name, reports)
+		log.Printf("已索引范围 %s", r)
+	}
+
+	idx, err := search.Open(paths.Data())
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("打开搜索索引失败: %v", err)), nil
+	}
+	if idx.Len() == 0 {
+		return mcp.NewToolResultError("搜索索引为空，请先采集报告，或传入 index_start_month 和 index_end_month"), nil
+	}
+
+	hits := idx.Search(q)
+	if len(hits) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("🔍 在 %d 份报告中没有找到 %q", idx.Len(), query)), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔍 在 %d 份报告中找到 %d 条结果", idx.Len(), len(hits))
+	if q.StartDate != "" || q.EndDate != "" {
+		fmt.Fprintf(&b, "（日期 %s ~ %s）", q.StartDate, q.EndDate)
+	}
+	b.WriteString("：\n\n")
+	for i, hit := range hits {
+		fmt.Fprintf(&b, "%d. [%s] %s\n", i+1, hit.Doc.Date, hit.Snippet)
+		if hit.Doc.Link != "" {
+			fmt.Fprintf(&b, "   链接：%s\n", hit.Doc.Link)
+		}
+	}
+
+	return mcp.NewToolResultText(b.String()), nil
+}
+
+// indexReports 将采集到的报告加入搜索索引，失败只记录日志
+func indexReports(reportType string, reports []collector.Report) {
+	if len(reports) == 0 {
+		return
+	}
+
+	var added int
+	idx, err := search.Update(paths.Data(), func(idx *search.Index) {
+		added = idx.AddReports(reportType, reports)
+	})
+	if err != nil {
+		log.Printf("更新搜索索引失败: %v", err)
+		return
+	}
+	log.Printf("✓ 搜索索引已更新：新增 %d 份，共 %d 份", added, idx.Len())
+}
+
+// handleStartCollection 启动后台采集任务
+func handleStartCollection(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	if jobManager == nil {
+		return mcp.NewToolResultError("后台任务不可用，请检查状态目录是否可写"), nil
+	}
+
+	r, errResult := parseDateRange(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	outputFile, _ := arguments["output_file"].(string)
+	reportType, _ := arguments["report_type"].(string)
+	if _, err := collector.GetReportType(reportType); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	onConflict, _ := arguments["on_conflict"].(string)
+	if _, err := fileutil.ParseConflict(onConflict); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	loginTimeout := 360
+	if val, ok := arguments["login_timeout"].(float64); ok {
+		loginTimeout = int(val)
+	}
+
+	params := map[string]string{
+		"range":           r.String(),
+		"report_type":     reportType,
+		"output_file":     outputFile,
+		"resume":          fmt.Sprint(arguments["resume"] == true),
+		"download_assets": fmt.Sprint(arguments["download_assets"] == true),
+		"on_conflict":     onConflict,
+	}
+	job, err := jobManager.Start("collection", params, func(ctx context.Context, p *jobs.Progress) (string, error) {
+		p.Stage("检查登录状态")
+		c, err := loginCollector(ctx, reportType, loginTimeout)
+		if err != nil {
+			return "", err
+		}
+		if err := setCollectOptions(c, arguments); err != nil {
+			return "", err
+		}
+
+		p.Stage("采集中")
+		c.OnProgress(func(done, total int, key string, reports []collector.Report) {
+			p.Step(done, total, fmt.Sprintf("%s：%d 条%s", key, len(reports), c.ReportType().Label))
+		})
+		result, err := c.Collect(r, outputFile)
+		if err != nil {
+			return "", err
+		}
+		indexReports(c.ReportType().Name, c.Collected())
+
+		p.Stage("已完成")
+		return result, nil
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("启动任务失败: %v", err)), nil
+	}
+
+	log.Printf("start_collection 工具被调用: %s, 任务: %s", r, job.ID)
+	return mcp.NewToolResultText(fmt.Sprintf("🚀 已启动后台采集任务 %s（范围 %s）\n\n使用 job_status 查看进度，job_result 获取结果，cancel_job 取消任务", job.ID, r)), nil
+}
+
+// handleJobStatus 查看任务状态
+func handleJobStatus(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	job, errResult := getJob(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+	return mcp.NewToolResultText(job.Format()), nil
+}
+
+// handleJobResult 获取任务结果
+func handleJobResult(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	job, errResult := getJob(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	switch job.Status {
+	case jobs.StatusSucceeded:
+		return mcp.NewToolResultText(job.Result), nil
+	case jobs.StatusRunning:
+		return mcp.NewToolResultText("⏳ 任务仍在运行\n\n" + job.Format()), nil
+	default:
+		return mcp.NewToolResultError(job.Format()), nil
+	}
+}
+
+// handleListJobs 列出任务
+func handleListJobs(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	if jobManager == nil {
+		return mcp.NewToolResultError("后台任务不可用，请检查状态目录是否可写"), nil
+	}
+
+	status, _ := arguments["status"].(string)
+	limit := 20
+	if val, ok := arguments["limit"].(float64); ok && val > 0 {
+		limit = int(val)
+	}
+
+	var lines []string
+	for _, job := range jobManager.List() {
+		if status != "" && job.Status != status {
+			continue
+		}
+		lines = append(lines, "- "+job.Summary())
+		if len(lines) >= limit {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		return mcp.NewToolResultText("没有后台任务"), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("📋 后台任务（%d 个）：\n\n%s", len(lines), strings.Join(lines, "\n"))), nil
+}
+
+// handleCancelJob 取消任务
+func handleCancelJob(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	job, errResult := getJob(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	log.Printf("cancel_job 工具被调用: %s", job.ID)
+	if err := jobManager.Cancel(job.ID); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("✓ 已请求取消任务 %s，稍后可用 job_status 确认", job.ID)), nil
+}
+
+// getJob 解析 job_id 参数并返回任务
+func getJob(arguments map[string]interface{}) (*jobs.Job, *mcp.CallToolResult) {
+	if jobManager == nil {
+		return nil, mcp.NewToolResultError("后台任务不可用，请检查状态目录是否可写")
+	}
+
+	id, _ := arguments["job_id"].(string)
+	if id == "" {
+		return nil, mcp.NewToolResultError("job_id 参数必须提供")
+	}
+
+	job, err := jobManager.Get(strings.TrimSpace(id))
+	if err != nil {
+		return nil, mcp.NewToolResultError(err.Error())
+	}
+	return job, nil
+}
+
+// handleListExportVersions 列出导出文件的历史版本
+func handleListExportVersions(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	filePath, errResult := exportPath(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	versions, err := fileutil.ListVersions(versionsDir(), filePath)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if len(versions) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("%s 没有历史版本", filePath)), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "📚 %s 的历史版本（%d 个，最新的在前）：\n\n", filePath, len(versions))
+	for _, v := range versions {
+		fmt.Fprintf(&b, "- %s（保存于 %s，%d 字节）\n", v.Name, v.SavedAt.Format("2006-01-02 15:04:05"), v.Size)
+	}
+	return mcp.NewToolResultText(b.String()), nil
+}
+
+// handleRestoreExportVersion 恢复导出文件的历史版本
+func handleRestoreExportVersion(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	filePath, errResult := exportPath(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+	version, _ := arguments["version"].(string)
+
+	log.Printf("restore_export_version 工具被调用: %s, 版本: %s", filePath, version)
+
+	backup, err := fileutil.Restore(versionsDir(), filePath, strings.TrimSpace(version))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result := fmt.Sprintf("✅ 已将 %s 恢复为版本 %s", filePath, version)
+	if backup != "" {
+		result += fmt.Sprintf("\n\n恢复前的文件已备份为 %s", filepath.Base(backup))
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// exportPath 解析 file_path 参数为绝对路径
+func exportPath(arguments map[string]interface{}) (string, *mcp.CallToolResult) {
+	filePath, _ := arguments["file_path"].(string)
+	if filePath == "" {
+		return "", mcp.NewToolResultError("file_path 参数必须提供")
+	}
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", mcp.NewToolResultError(fmt.Sprintf("路径无效: %v", err))
+	}
+	return abs, nil
+}
+
+// versionsDir 返回导出文件历史版本的保存目录
+func versionsDir() string {
+	return filepath.Join(paths.Data(), fileutil.VersionsDirName)
+}
+
+// withExportSource 为导出工具追加数据来源（md_file_path 或日期范围）和输出参数
+func withExportSource(opts ...mcp.ToolOption) []mcp.ToolOption {
+	opts = append(opts,
+		mcp.WithString("md_file_path",
+			mcp.Description("已导出的日报 MD 文件路径（与日期范围二选一）"),
+		),
+		mcp.WithString("report_type",
+			mcp.DefaultString(collector.TypeDaily),
+			mcp.Enum(collector.ReportTypeNames()...),
+			mcp.Description("报告类型：daily 日报、weekly 周报、monthly 月报，默认 daily"),
+		),
+		mcp.WithString("output_file",
+			mcp.Description("输出文件路径（可选，默认输出目录下的 日报汇总_<范围>.<扩展名>）"),
+		),
+		mcp.WithString("on_conflict",
+			mcp.DefaultString(fileutil.ConflictOverwrite),
+			mcp.Enum(fileutil.ConflictModes()...),
+			mcp.Description("输出文件已存在时：overwrite 覆盖（旧文件自动备份）、version 另存为新文件、fail 报错，默认 overwrite"),
+		),
+	)
+	return withDateRange(opts...)
+}
+
+// loadSummary 读取 MD 文件或按日期范围采集，按月提取任务和权重，日报额外计算提交统计
+func loadSummary(arguments map[string]interface{}) (*export.Summary, daterange.Range, *mcp.CallToolResult) {
+	mdFilePath, _ := arguments["md_file_path"].(string)
+	reportType, _ := arguments["report_type"].(string)
+	rt, err := collector.GetReportType(reportType)
+	if err != nil {
+		return nil, daterange.Range{}, mcp.NewToolResultError(err.Error())
+	}
+
+	var (
+		r       daterange.Range
+		months  []string
+		byMonth map[string][]collector.Report
+	)
+	if mdFilePath != "" {
+		content, err := os.ReadFile(mdFilePath)
+		if err != nil {
+			return nil, r, mcp.NewToolResultError(fmt.Sprintf("读取 MD 文件失败: %v", err))
+		}
+		reports := collector.ParseMarkdownReports(string(content))
+		localizeAttachmentPaths(reports, filepath.Dir(mdFilePath))
+		months, byMonth = export.GroupByMonth(reports)
+		if len(months) == 0 {
+			return nil, r, mcp.NewToolResultError("MD 文件中没有带日期的报告")
+		}
+		if r, err = daterange.Between(months[0], months[len(months)-1], time.Now()); err != nil {
+			return nil, r, mcp.NewToolResultError(fmt.Sprintf("日期范围错误: %v", err))
+		}
+	} else {
+		var errResult *mcp.CallToolResult
+		if r, errResult = parseDateRange(arguments); errResult != nil {
+			return nil, r, errResult
+		}
+		c, err := newCollector(rt.Name)
+		if err != nil {
+			return nil, r, mcp.NewToolResultError(err.Error())
+		}
+		if months, byMonth, err = c.CollectRange(r); err != nil {
+			return nil, r, mcp.NewToolResultError(fmt.Sprintf("采集失败: %v", err))
+		}
+	}
+
+	extractor, err := loadExtractor()
+	if err != nil {
+		return nil, r, mcp.NewToolResultError(fmt.Sprintf("加载项目词典失败: %v", err))
+	}
+	summary := export.NewSummary(rt.Label, r.String(), months, byMonth, extractor, summaryTaskLimit)
+
+	if rt.Name == collector.TypeDaily {
+		cal, err := loadCalendar("", "")
+		if err != nil {
+			return nil, r, mcp.NewToolResultError(fmt.Sprintf("加载工作日历失败: %v", err))
+		}
+		end := r.End
+		if cutoff := reportCutoff(end.Location()); end.After(cutoff) {
+			end = cutoff
+		}
+		if summary.Stats, err = analysis.ComputeStats(months, byMonth, cal, r.Start, end); err != nil {
+			return nil, r, mcp.NewToolResultError(fmt.Sprintf("统计失败: %v", err))
+		}
+	}
+	return summary, r, nil
+}
+
+// localizeAttachmentPaths 把 MD 文件中相对 MD 所在目录的附件路径转换为绝对路径
+func localizeAttachmentPaths(reports []collector.Report, dir string) {
+	for i := range reports {
+		for j := range reports[i].Attachments {
+			a := &reports[i].Attachments[j]
+			if a.Local != "" && !filepath.IsAbs(a.Local) {
+				a.Local = filepath.Join(dir, filepath.FromSlash(a.Local))
+			}
+		}
+	}
+}
+
+// outputPath 返回 output_file 对应的导出路径，未指定时为输出目录下的 defaultName
+func outputPath(arguments map[string]interface{}, defaultName string) string {
+	outputFile, _ := arguments["output_file"].(string)
+	if outputFile == "" {
+		outputFile = defaultName
+	}
+	outputFile = paths.ExpandHome(outputFile)
+	if !filepath.IsAbs(outputFile) {
+		outputFile = filepath.Join(paths.Output(), outputFile)
+	}
+	return outputFile
+}
+
+// writeExport 按 output_file / on_conflict 原子写出导出文件，返回最终路径
+func writeExport(arguments map[string]interface{}, defaultName string, write func(w io.Writer) error) (string, *mcp.CallToolResult) {
+	onConflict, _ := arguments["on_conflict"].(string)
+	mode, err := fileutil.ParseConflict(onConflict)
+	if err != nil {
+		return "", mcp.NewToolResultError(err.Error())
+	}
+
+	outputFile := outputPath(arguments, defaultName)
+	f, err := fileutil.Create(outputFile, fileutil.Options{OnConflict: mode, BackupDir: versionsDir()})
+	if err != nil {
+		return "", mcp.NewToolResultError(err.Error())
+	}
+	defer f.Abort()
+
+	if err := write(f); err != nil {
+		return "", mcp.NewToolResultError(fmt.Sprintf("生成文件失败: %v", err))
+	}
+	path, err := f.Commit()
+	if err != nil {
+		return "", mcp.NewToolResultError(err.Error())
+	}
+	return path, nil
+}
+
+// exportName 返回默认导出文件名，例如 日报汇总_2025-03.xlsx、日报汇总_2025-03-10_2025-03-21.xlsx
+func exportName(s *export.Summary, r daterange.Range, ext string) string {
+	period := r.Start.Format(daterange.DateLayout) + "_" + r.End.Format(daterange.DateLayout)
+	if months := r.Months(); len(months) == 1 && r.Start.Day() == 1 && r.End.AddDate(0, 0, 1).Day() == 1 {
+		period = months[0]
+	}
+	return fmt.Sprintf("%s汇总_%s%s", s.Label, period, ext)
+}
+
+// handleExportXLSX 导出 Excel 工作簿
+func handleExportXLSX(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	log.Printf("export_xlsx 工具被调用")
+
+	summary, r, errResult := loadSummary(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	path, errResult := writeExport(arguments, exportName(summary, r, ".xlsx"), func(w io.Writer) error {
+		return export.WriteXLSX(summary, w)
+	})
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("✅ 已导出 Excel：%s\n\n范围 %s，共 %d 份%s，%d 张月度汇总表",
+		path, r, len(summary.Reports), summary.Label, len(summary.Months))), nil
+}
+
+// handleExportDOCX 导出 Word 工作总结
+func handleExportDOCX(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, _ := arguments["name"].(string)
+	templatePath, _ := arguments["template"].(string)
+
+	log.Printf("export_docx 工具被调用: template=%s", templatePath)
+
+	// 未指定模板时使用配置目录下的默认模板（如果有）
+	if templatePath == "" {
+		if p := filepath.Join(paths.Config(), export.DocxTemplateFileName); fileExists(p) {
+			templatePath = p
+		}
+	}
+	opts := export.DocxOptions{Name: strings.TrimSpace(name)}
+	if templatePath != "" {
+		tpl, err := zip.OpenReader(paths.ExpandHome(templatePath))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("读取 Word 模板失败: %v", err)), nil
+		}
+		defer tpl.Close()
+		opts.Template = &tpl.Reader
+	}
+
+	summary, r, errResult := loadSummary(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	path, errResult := writeExport(arguments, exportName(summary, r, ".docx"), func(w io.Writer) error {
+		return export.WriteDOCX(summary, opts, w)
+	})
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	result := fmt.Sprintf("✅ 已导出 Word：%s\n\n范围 %s，共 %d 份%s", path, r, len(summary.Reports), summary.Label)
+	if templatePath != "" {
+		result += "\n模板：" + templatePath
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// handleExportHTML 导出 HTML 工作总结
+func handleExportHTML(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	log.Printf("export_html 工具被调用")
+
+	name, _ := arguments["name"].(string)
+	summary, r, errResult := loadSummary(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	// 附件链接相对 HTML 文件所在目录
+	defaultName := exportName(summary, r, ".html")
+	opts := export.HTMLOptions{Name: strings.TrimSpace(name), AssetBase: filepath.Dir(outputPath(arguments, defaultName))}
+	path, errResult := writeExport(arguments, defaultName, func(w io.Writer) error {
+		return export.WriteHTML(summary, opts, w)
+	})
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("✅ 已导出 HTML：%s\n\n范围 %s，共 %d 份%s", path, r, len(summary.Reports), summary.Label)), nil
+}
+
+// handleExportPDF 渲染 HTML 导出并用无头 Chrome 打印为 PDF
+func handleExportPDF(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, _ := arguments["name"].(string)
+	name = strings.TrimSpace(name)
+	paper, _ := arguments["paper"].(string)
+	landscape, _ := arguments["landscape"].(bool)
+	margins, _ := arguments["margins"].(string)
+	header, _ := arguments["header"].(string)
+	footer, _ := arguments["footer"].(string)
+	headerFooter := true
+	if val, ok := arguments["header_footer"].(bool); ok {
+		headerFooter = val
+	}
+
+	log.Printf("export_pdf 工具被调用: paper=%s, landscape=%v, margins=%s", paper, landscape, margins)
+
+	opts := browser.PDFOptions{
+		Paper:        paper,
+		Landscape:    landscape,
+		HeaderFooter: headerFooter,
+		Header:       strings.TrimSpace(header),
+		Footer:       strings.TrimSpace(footer),
+	}
+	var err error
+	if opts.Margins, err = browser.ParseMargins(margins); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	summary, r, errResult := loadSummary(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if opts.Footer == "" {
+		opts.Footer = strings.TrimSpace(name + "　" + summary.Period)
+	}
+
+	var doc strings.Builder
+	if err := export.WriteHTML(summary, export.HTMLOptions{Name: name, EmbedImages: true}, &doc); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	pdf, err := browser.PrintPDF(context.Background(), doc.String(), opts)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	path, errResult := writeExport(arguments, exportName(summary, r, ".pdf"), func(w io.Writer) error {
+		_, err := w.Write(pdf)
+		return err
+	})
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("✅ 已导出 PDF：%s\n\n范围 %s，共 %d 份%s，%d KB",
+		path, r, len(summary.Reports), summary.Label, (len(pdf)+1023)/1024)), nil
+}
+
+// handleExportICS 导出 iCalendar 日历：每份报告一个全天事件，可选明日计划事件
+func handleExportICS(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, _ := arguments["name"].(string)
+	plans, _ := arguments["plan_events"].(bool)
+	holidays, _ := arguments["holidays"].(string)
+	workdays, _ := arguments["workdays"].(string)
+
+	log.Printf("export_ics 工具被调用: plan_events=%v", plans)
+
+	summary, r, errResult := loadSummary(arguments)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	opts := export.ICSOptions{Name: strings.TrimSpace(name), Plans: plans}
+	if plans {
+		cal, err := loadCalendar(holidays, workdays)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("加载工作日历失败: %v", err)), nil
+		}
+		opts.Calendar = cal
+	}
+	events := export.ReportEvents(summary, opts)
+	planCount := 0
+	for _, e := range events {
+		if e.Plan {
+			planCount++
+		}
+	}
+	reportCount := len(events) - planCount
+
+	path, errResult := writeExport(arguments, exportName(summary, r, ".ics"), func(w io.Writer) error {
+		return export.WriteICS(summary, opts, events, w)
+	})
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	result := fmt.Sprintf("✅ 已导出日历：%s\n\n范围 %s，%d 个%s事件", path, r, reportCount, summary.Label)
+	if plans {
+		result += fmt.Sprintf("，%d 个%s事件", planCount, export.PlanCategory)
+	}
+	if skipped := len(summary.Reports) - reportCount; skipped > 0 {
+		result += fmt.Sprintf("\n⚠️ %d 份%s没有日期，已跳过", skipped, summary.Label)
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// handleArchiveReportPages 启动后台任务，存档报告页面的截图和 MHTML 快照
+func handleArchiveReportPages(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	if jobManager == nil {
+		return mcp.NewToolResultError("后台任务不可用，请检查状态目录是否可写"), nil
+	}
+
+	mdFilePath, _ := arguments["md_file_path"].(string)
+	reportType, _ := arguments["report_type"].(string)
+	rt, err := collector.GetReportType(reportType)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var (
+		r       daterange.Range
+		reports []collector.Report
+	)
+	if mdFilePath != "" {
+		content, err := os.ReadFile(mdFilePath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("读取 MD 文件失败: %v", err)), nil
+		}
+		reports = collector.ParseMarkdownReports(string(content))
+		if len(reports) == 0 {
+			return mcp.NewToolResultError("MD 文件中没有报告"), nil
+		}
+	} else {
+		var errResult *mcp.CallToolResult
+		if r, errResult = parseDateRange(arguments); errResult != nil {
+			return errResult, nil
+		}
+	}
+
+	root, _ := arguments["output_dir"].(string)
+	if root == "" {
+		root = filepath.Join(paths.Data(), archive.DirName)
+	}
+	root = paths.ExpandHome(root)
+
+	pageTimeout := browser.DefaultPageTimeout
+	if val, ok := arguments["page_timeout"].(float64); ok && val > 0 {
+		pageTimeout = time.Duration(val) * time.Second
+	}
+	loginTimeout := 360
+	if val, ok := arguments["login_timeout"].(float64); ok {
+		loginTimeout = int(val)
+	}
+
+	scope := mdFilePath
+	if scope == "" {
+		scope = r.String()
+	}
+	params := map[string]string{
+		"source":      scope,
+		"report_type": rt.Name,
+		"output_dir":  root,
+	}
+	job, err := jobManager.Start("archive", params, func(ctx context.Context, p *jobs.Progress) (string, error) {
+		p.Stage("检查登录状态")
+		c, err := loginCollector(ctx, rt.Name, loginTimeout)
+		if err != nil {
+			return "", err
+		}
+
+		if mdFilePath == "" {
+			p.Stage("采集报告列表")
+			c.SetContext(ctx)
+			_, byMonth, err := c.CollectRange(r)
+			if err != nil {
+				return "", fmt.Errorf("采集失败: %w", err)
+			}
+			for _, items := range byMonth {
+				reports = append(reports, items...)
+			}
+			sort.SliceStable(reports, func(i, j int) bool {
+				return reports[i].Date.Before(reports[j].Date)
+			})
+		}
+		if len(reports) == 0 {
+			return fmt.Sprintf("%s 没有%s，无需存档", scope, rt.Label), nil
+		}
+
+		p.Stage("存档页面")
+		dir, manifest, err := archive.Run(ctx, reports, archive.Options{
+			Root:        root,
+			ReportType:  rt.Name,
+			Range:       scope,
+			Profile:     paths.Profile(),
+			PageTimeout: pageTimeout,
+		}, func(done, total int, item archive.Item) {
+			status := "✓"
+			if item.Error != "" {
+				status = "✗ " + item.Error
+			}
+			p.Step(done, total, fmt.Sprintf("%s %s", item.Title, status))
+		})
+		if err != nil {
+			if manifest != nil {
+				return "", fmt.Errorf("%w（已存档 %d 份，清单：%s）", err, len(manifest.Items)-manifest.Failed(), filepath.Join(dir, archive.ManifestFileName))
+			}
+			return "", err
+		}
+
+		p.Stage("已完成")
+		result := fmt.Sprintf("✅ 已存档 %d 份%s页面：%s\n清单：%s",
+			len(manifest.Items)-manifest.Failed(), rt.Label, dir, filepath.Join(dir, archive.ManifestFileName))
+		if failed := manifest.Failed(); failed > 0 {
+			result += fmt.Sprintf("\n⚠️ %d 份失败，原因见清单中的 error 字段", failed)
+		}
+		return result, nil
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("启动任务失败: %v", err)), nil
+	}
+
+	log.Printf("archive_report_pages 工具被调用: %s, 任务: %s", scope, job.ID)
+	return mcp.NewToolResultText(fmt.Sprintf("🚀 已启动后台存档任务 %s（%s）\n\n使用 job_status 查看进度，job_result 获取结果，cancel_job 取消任务", job.ID, scope)), nil
+}
+
+// viewPageMaxHeight view_report_page 截图的默认最大高度（像素），避免长页面的截图过大
+const viewPageMaxHeight = 4000
+
+// handleViewReportPage 截图报告页面，以图片内容返回并附上页面文本
+func handleViewReportPage(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	link, _ := arguments["link"].(string)
+	date, _ := arguments["date"].(string)
+	reportType, _ := arguments["report_type"].(string)
+
+	log.Printf("view_report_page 工具被调用: link=%s, date=%s", link, date)
+
+	opts := browser.CaptureOptions{Timeout: browser.DefaultPageTimeout, Quality: 80, MaxHeight: viewPageMaxHeight}
+	if val, ok := arguments["quality"].(float64); ok {
+		opts.Quality = int(val)
+	}
+	if val, ok := arguments["max_height"].(float64); ok && val > 0 {
+		opts.MaxHeight = int(val)
+	}
+	if val, ok := arguments["timeout"].(float64); ok && val > 0 {
+		opts.Timeout = time.Duration(val) * time.Second
+	}
+
+	title := link
+	if link == "" {
+		if date == "" {
+			return mcp.NewToolResultError("需要提供 link 或 date 参数"), nil
+		}
+		report, errResult := findReport(date, reportType)
+		if errResult != nil {
+			return errResult, nil
+		}
+		link = report.Link
+		title, _, _ = strings.Cut(report.Text, "\n")
+	}
+	pageURL, err := collector.ResolveLink(link)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	session, err := browser.NewSession(context.Background())
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer session.Close()
+
+	c, err := session.Capture(pageURL, opts)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	shot := fmt.Sprintf("%s，%d KB", c.MIMEType, (len(c.Image)+1023)/1024)
+	if c.Truncated {
+		shot += fmt.Sprintf("（页面高 %d 像素，截图只包含顶部 %d 像素，其余内容见下方文本）", c.PageHeight, opts.MaxHeight)
+	}
+	text := fmt.Sprintf("📄 %s\n链接：%s\n页面标题：%s\n截图：%s\n\n%s",
+		title, c.URL, c.Title, shot, c.Text)
+	return mcp.NewToolResultImage(text, base64.StdEncoding.EncodeToString(c.Image), c.MIMEType), nil
+}
+
+// findReport 按日期在报告列表中查找报告：日报为当天，周报为当周，月报为当月
+func findReport(date, reportType string) (*collector.Report, *mcp.CallToolResult) {
+	c, err := newCollector(reportType)
+	if err != nil {
+		return nil, mcp.NewToolResultError(err.Error())
+	}
+	rt := c.ReportType()
+
+	r, err := daterange.Parse(date, time.Now())
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("日期错误: %v", err))
+	}
+	if rt.PeriodOf(r.Start) != rt.PeriodOf(r.End) {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("%q 跨越多个%s周期，请指定具体日期", date, rt.Label))
+	}
+
+	if err := c.EnsureLogin(); err != nil {
+		return nil, mcp.NewToolResultError(err.Error())
+	}
+	report, err := c.FindReportByDate(r.Start)
+	if err != nil {
+		return nil, mcp.NewToolResultError(err.Error())
+	}
+	if report == nil || report.Link == "" {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("%s 没有找到%s", rt.PeriodOf(r.Start), rt.Label))
+	}
+	return report, nil
+}
+
+// fileExists 判断文件是否存在
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,190 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/calendar"
+	"github.com/Xuzan9396/yst_go_mcp/internal/collector"
+)
+
+// LateReport 补交的日报
+type LateReport struct {
+	Date        time.Time
+	SubmittedAt time.Time
+	Link        string
+}
+
+// DuplicateReport 同一天提交了多份的日报
+type DuplicateReport struct {
+	Date  time.Time
+	Links []string
+}
+
+// GapResult 日报缺失检测结果
+type GapResult struct {
+	Start      time.Time
+	End        time.Time
+	Workdays   int
+	Missing    []time.Time
+	Late       []LateReport
+	Duplicates []DuplicateReport
+	Undated    []collector.Report
+	// MissingYears 没有节假日数据、只按周末计算的年份
+	MissingYears []int
+	// Failed 采集失败、未参与检测的周期（月份 YYYY-MM 或年份 YYYY）
+	Failed []string
+}
+
+// ExcludeFailed 去掉采集失败周期内的工作日：这些日期没有数据，不能算作缺失
+func (g *GapResult) ExcludeFailed(keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	g.Failed = append(g.Failed, keys...)
+
+	// 失败周期内没有任何报告，其工作日都在 Missing 中
+	var missing []time.Time
+	for _, day := range g.Missing {
+		if inPeriods(day, keys) {
+			g.Workdays--
+			continue
+		}
+		missing = append(missing, day)
+	}
+	g.Missing = missing
+}
+
+// inPeriods 判断日期是否属于某个周期（YYYY-MM 或 YYYY）
+func inPeriods(day time.Time, keys []string) bool {
+	date := day.Format(calendar.DateLayout)
+	for _, key := range keys {
+		if strings.HasPrefix(date, key+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// FindGaps 对比工作日历，找出 [start, end] 区间内缺失、补交和重复的日报
+//
+// start、end 可以在任意时区，只按年月日比较
+func FindGaps(reports []collector.Report, cal *calendar.Calendar, start, end time.Time) *GapResult {
+	start, end = localDay(start), localDay(end)
+	first, last := start.Format(calendar.DateLayout), end.Format(calendar.DateLayout)
+	result := &GapResult{
+		Start:        start,
+		End:          end,
+		MissingYears: cal.MissingYears(start, end),
+	}
+
+	byDate := make(map[string][]collector.Report)
+	for _, r := range reports {
+		if r.Date.IsZero() {
+			result.Undated = append(result.Undated, r)
+			continue
+		}
+		key := r.Date.Format(calendar.DateLayout)
+		if key < first || key > last {
+			continue
+		}
+		byDate[key] = append(byDate[key], r)
+	}
+
+	for _, day := range cal.Workdays(start, end) {
+		result.Workdays++
+		if len(byDate[day.Format(calendar.DateLayout)]) == 0 {
+			result.Missing = append(result.Missing, day)
+		}
+	}
+
+	var keys []string
+	for key := range byDate {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		items := byDate[key]
+		if len(items) > 1 {
+			dup := DuplicateReport{Date: items[0].Date}
+			for _, r := range items {
+				dup.Links = append(dup.Links, r.Link)
+			}
+			result.Duplicates = append(result.Duplicates, dup)
+		}
+		for _, r := range items {
+			if !r.SubmittedAt.IsZero() && r.SubmittedAt.Format(calendar.DateLayout) > key {
+				result.Late = append(result.Late, LateReport{
+					Date:        r.Date,
+					SubmittedAt: r.SubmittedAt,
+					Link:        r.Link,
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+// localDay 把日期转换为本地时区的同一天零点：报告日期按本地时区解析，
+// 而日期范围可能使用配置的其他时区，直接按时刻比较会在边界上差一天
+func localDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// Format 生成可读的检测报告
+func (g *GapResult) Format() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "📅 检测区间：%s ~ %s，共 %d 个工作日\n\n",
+		g.Start.Format(calendar.DateLayout), g.End.Format(calendar.DateLayout), g.Workdays)
+	if len(g.MissingYears) > 0 {
+		fmt.Fprintf(&b, "⚠ %v 年没有节假日数据，仅排除了周末，可在配置目录 calendar/ 下补充\n\n", g.MissingYears)
+	}
+
+	if len(g.Failed) > 0 {
+		fmt.Fprintf(&b, "⚠ %s 采集失败，未参与检测（不代表缺失），请稍后重试\n\n", strings.Join(g.Failed, "、"))
+	}
+
+	fmt.Fprintf(&b, "## 缺失日报 (%d 天)\n\n", len(g.Missing))
+	if len(g.Missing) == 0 {
+		b.WriteString("✓ 没有缺失\n")
+	}
+	for _, d := range g.Missing {
+		fmt.Fprintf(&b, "- %s %s\n", d.Format(calendar.DateLayout), weekdayName(d.Weekday()))
+	}
+
+	fmt.Fprintf(&b, "\n## 补交日报 (%d 份)\n\n", len(g.Late))
+	if len(g.Late) == 0 {
+		b.WriteString("✓ 没有补交\n")
+	}
+	for _, l := range g.Late {
+		fmt.Fprintf(&b, "- %s 于 %s 提交 %s\n",
+			l.Date.Format(calendar.DateLayout), l.SubmittedAt.Format("2006-01-02 15:04"), l.Link)
+	}
+
+	fmt.Fprintf(&b, "\n## 重复日报 (%d 天)\n\n", len(g.Duplicates))
+	if len(g.Duplicates) == 0 {
+		b.WriteString("✓ 没有重复\n")
+	}
+	for _, d := range g.Duplicates {
+		fmt.Fprintf(&b, "- %s 共 %d 份：%s\n",
+			d.Date.Format(calendar.DateLayout), len(d.Links), strings.Join(d.Links, "、"))
+	}
+
+	if len(g.Undated) > 0 {
+		fmt.Fprintf(&b, "\n## 无法识别日期 (%d 份)\n\n", len(g.Undated))
+		for _, r := range g.Undated {
+			fmt.Fprintf(&b, "- %s %s\n", r.Text, r.Link)
+		}
+	}
+
+	return b.String()
+}
+
+// weekdayName 返回中文星期
+func weekdayName(w time.Weekday) string {
+	return [...]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"}[w]
+}
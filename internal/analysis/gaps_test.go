@@ -0,0 +1,68 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/calendar"
+	"github.com/Xuzan9396/yst_go_mcp/internal/collector"
+	"github.com/Xuzan9396/yst_go_mcp/internal/daterange"
+)
+
+// 报告日期按本地时区解析，日期范围使用配置的其他时区时，边界上的日报也应计入。
+// 东西两侧的时区至少有一个与本地时区不同
+func TestFindGapsRangeInOtherTimezone(t *testing.T) {
+	for _, zone := range []string{"Asia/Shanghai", "America/Los_Angeles", "UTC"} {
+		t.Run(zone, func(t *testing.T) {
+			loc, err := time.LoadLocation(zone)
+			if err != nil {
+				t.Skipf("没有时区数据: %v", err)
+			}
+			r, err := daterange.Parse("2025-03-31", time.Date(2025, 4, 1, 9, 0, 0, 0, loc))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			date, _ := collector.ParseReportDates("2025-03-31 日报")
+			reports := []collector.Report{{Text: "2025-03-31 日报", Date: date}}
+			if !r.Contains(date) {
+				t.Fatalf("范围 %s 应包含 %s", r, date)
+			}
+
+			gaps := FindGaps(reports, calendar.New(), r.Start, r.End)
+			if gaps.Workdays != 1 || len(gaps.Missing) != 0 {
+				t.Errorf("FindGaps: workdays=%d missing=%v，期望 1 个工作日且没有缺失", gaps.Workdays, gaps.Missing)
+			}
+
+			stats, err := ComputeStats([]string{"2025-03"}, map[string][]collector.Report{"2025-03": reports}, calendar.New(), r.Start, r.End)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.Workdays != 1 || stats.ReportedDays != 1 {
+				t.Errorf("ComputeStats: workdays=%d reported=%d，期望 1/1", stats.Workdays, stats.ReportedDays)
+			}
+		})
+	}
+}
+
+func TestGapResultExcludeFailed(t *testing.T) {
+	cal := calendar.New()
+	start := time.Date(2025, 3, 28, 0, 0, 0, 0, time.Local)
+	end := time.Date(2025, 4, 2, 0, 0, 0, 0, time.Local)
+
+	// 3 月有一份日报，4 月采集失败
+	date, _ := collector.ParseReportDates("2025-03-28 日报")
+	gaps := FindGaps([]collector.Report{{Text: "2025-03-28 日报", Date: date}}, cal, start, end)
+	gaps.ExcludeFailed([]string{"2025-04"})
+
+	var missing []string
+	for _, d := range gaps.Missing {
+		missing = append(missing, d.Format(calendar.DateLayout))
+	}
+	if len(missing) != 1 || missing[0] != "2025-03-31" {
+		t.Errorf("missing = %v，期望只有 2025-03-31", missing)
+	}
+	if gaps.Workdays != 2 {
+		t.Errorf("workdays = %d，期望 2（不含采集失败的 4 月）", gaps.Workdays)
+	}
+}
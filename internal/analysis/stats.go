@@ -0,0 +1,238 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/calendar"
+	"github.com/Xuzan9396/yst_go_mcp/internal/collector"
+)
+
+// hoursRe 匹配日报中的工时，例如 "8h"、"7.5 小时"、"工时：8"
+var hoursRe = regexp.MustCompile(`(?i)(?:工时[:：]\s*(\d+(?:\.\d+)?))|(\d+(?:\.\d+)?)\s*(?:h\b|小时)`)
+
+// MonthStats 单月统计
+type MonthStats struct {
+	Month          string  `json:"month"`
+	Reports        int     `json:"reports"`
+	Workdays       int     `json:"workdays"`
+	ReportedDays   int     `json:"reported_days"`
+	SubmissionRate float64 `json:"submission_rate"`
+	AvgLength      float64 `json:"avg_length"`
+	TotalHours     float64 `json:"total_hours"`
+}
+
+// WeekdayCount 按星期统计的日报数
+type WeekdayCount struct {
+	Weekday string `json:"weekday"`
+	Reports int    `json:"reports"`
+}
+
+// Streak 连续提交日报的工作日区间
+type Streak struct {
+	Days  int    `json:"days"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Stats 日报统计结果
+type Stats struct {
+	Months         []MonthStats   `json:"months"`
+	TotalReports   int            `json:"total_reports"`
+	Workdays       int            `json:"workdays"`
+	ReportedDays   int            `json:"reported_days"`
+	SubmissionRate float64        `json:"submission_rate"`
+	AvgLength      float64        `json:"avg_length"`
+	TotalHours     float64        `json:"total_hours"`
+	AvgHours       float64        `json:"avg_hours"`
+	BusiestDays    []WeekdayCount `json:"busiest_weekdays"`
+	LongestStreak  Streak         `json:"longest_streak"`
+}
+
+// ComputeStats 统计月份范围内的日报数据，[start, end] 之外的工作日不计入提交率（只按年月日比较）
+func ComputeStats(months []string, allReports map[string][]collector.Report, cal *calendar.Calendar, start, end time.Time) (*Stats, error) {
+	start, end = localDay(start), localDay(end)
+	stats := &Stats{}
+	reported := make(map[string]bool)
+	weekdays := make([]int, 7)
+	totalLength := 0
+	hoursReports := 0
+
+	for _, month := range months {
+		ms := MonthStats{Month: month}
+		reports := allReports[month]
+		ms.Reports = len(reports)
+
+		monthLength := 0
+		monthDays := make(map[string]bool)
+		for _, r := range reports {
+			length := utf8.RuneCountInString(r.Text)
+			monthLength += length
+			if h, ok := ParseHours(r.Text); ok {
+				ms.TotalHours += h
+				hoursReports++
+			}
+			if !r.Date.IsZero() {
+				key := r.Date.Format(calendar.DateLayout)
+				if !monthDays[key] {
+					monthDays[key] = true
+					weekdays[r.Date.Weekday()]++
+				}
+				reported[key] = true
+			}
+		}
+		if ms.Reports > 0 {
+			ms.AvgLength = float64(monthLength) / float64(ms.Reports)
+		}
+		totalLength += monthLength
+
+		monthStart, monthEnd, err := calendar.MonthBounds(month)
+		if err != nil {
+			return nil, err
+		}
+		if monthStart.Before(start) {
+			monthStart = start
+		}
+		if monthEnd.After(end) {
+			monthEnd = end
+		}
+		for _, day := range cal.Workdays(monthStart, monthEnd) {
+			ms.Workdays++
+			if monthDays[day.Format(calendar.DateLayout)] {
+				ms.ReportedDays++
+			}
+		}
+		ms.SubmissionRate = rate(ms.ReportedDays, ms.Workdays)
+
+		stats.Months = append(stats.Months, ms)
+		stats.TotalReports += ms.Reports
+		stats.Workdays += ms.Workdays
+		stats.ReportedDays += ms.ReportedDays
+		stats.TotalHours += ms.TotalHours
+	}
+
+	stats.SubmissionRate = rate(stats.ReportedDays, stats.Workdays)
+	if stats.TotalReports > 0 {
+		stats.AvgLength = float64(totalLength) / float64(stats.TotalReports)
+	}
+	if hoursReports > 0 {
+		stats.AvgHours = stats.TotalHours / float64(hoursReports)
+	}
+
+	for i, n := range weekdays {
+		if n > 0 {
+			stats.BusiestDays = append(stats.BusiestDays, WeekdayCount{
+				Weekday: weekdayName(time.Weekday(i)),
+				Reports: n,
+			})
+		}
+	}
+	sort.SliceStable(stats.BusiestDays, func(i, j int) bool {
+		return stats.BusiestDays[i].Reports > stats.BusiestDays[j].Reports
+	})
+
+	if len(months) > 0 {
+		first, _, _ := calendar.MonthBounds(months[0])
+		_, last, _ := calendar.MonthBounds(months[len(months)-1])
+		if first.Before(start) {
+			first = start
+		}
+		if last.After(end) {
+			last = end
+		}
+		stats.LongestStreak = longestStreak(cal.Workdays(first, last), reported)
+	}
+
+	return stats, nil
+}
+
+// ParseHours 从日报文本中提取工时
+func ParseHours(text string) (float64, bool) {
+	total := 0.0
+	found := false
+	for _, m := range hoursRe.FindAllStringSubmatch(text, -1) {
+		v := m[1]
+		if v == "" {
+			v = m[2]
+		}
+		h, err := strconv.ParseFloat(v, 64)
+		if err != nil || h <= 0 || h > 24 {
+			continue
+		}
+		total += h
+		found = true
+	}
+	return total, found
+}
+
+// longestStreak 计算连续提交日报的最长工作日区间（节假日不打断连续）
+func longestStreak(workdays []time.Time, reported map[string]bool) Streak {
+	var best, cur Streak
+	for _, day := range workdays {
+		key := day.Format(calendar.DateLayout)
+		if !reported[key] {
+			cur = Streak{}
+			continue
+		}
+		if cur.Days == 0 {
+			cur.Start = key
+		}
+		cur.Days++
+		cur.End = key
+		if cur.Days > best.Days {
+			best = cur
+		}
+	}
+	return best
+}
+
+// rate 计算百分比，保留一位小数
+func rate(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(int(float64(n)*1000/float64(total)+0.5)) / 10
+}
+
+// Table 生成可读的统计表格
+func (s *Stats) Table() string {
+	var b strings.Builder
+	b.WriteString("| 月份 | 日报数 | 工作日 | 已提交天数 | 提交率 | 平均字数 | 工时 |\n")
+	b.WriteString("|------|--------|--------|------------|--------|----------|------|\n")
+	for _, m := range s.Months {
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %.1f%% | %.0f | %s |\n",
+			m.Month, m.Reports, m.Workdays, m.ReportedDays, m.SubmissionRate, m.AvgLength, formatHours(m.TotalHours))
+	}
+	fmt.Fprintf(&b, "| **合计** | %d | %d | %d | %.1f%% | %.0f | %s |\n\n",
+		s.TotalReports, s.Workdays, s.ReportedDays, s.SubmissionRate, s.AvgLength, formatHours(s.TotalHours))
+
+	if s.AvgHours > 0 {
+		fmt.Fprintf(&b, "- 平均工时：%.1f 小时/份\n", s.AvgHours)
+	}
+	if len(s.BusiestDays) > 0 {
+		var parts []string
+		for _, d := range s.BusiestDays {
+			parts = append(parts, fmt.Sprintf("%s %d", d.Weekday, d.Reports))
+		}
+		fmt.Fprintf(&b, "- 按星期分布：%s\n", strings.Join(parts, "、"))
+	}
+	if s.LongestStreak.Days > 0 {
+		fmt.Fprintf(&b, "- 最长连续提交：%d 个工作日（%s ~ %s）\n",
+			s.LongestStreak.Days, s.LongestStreak.Start, s.LongestStreak.End)
+	}
+
+	return b.String()
+}
+
+// formatHours 工时为 0 时显示 "-"
+func formatHours(h float64) string {
+	if h == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(h, 'f', 1, 64)
+}
@@ -0,0 +1,409 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/calendar"
+	"github.com/Xuzan9396/yst_go_mcp/internal/collector"
+)
+
+// KeywordsFileName 配置目录下的项目词典配置文件
+const KeywordsFileName = "keywords.json"
+
+var (
+	// entrySplitRe 日报条目分隔：换行、分号、句号
+	entrySplitRe = regexp.MustCompile(`[\n;；。]+`)
+	// numberingRe 条目序号，例如 "1." "2、" "(3)"
+	numberingRe = regexp.MustCompile(`(?:^|\s+)(?:\d{1,2}[.、)）]|[（(]\d{1,2}[)）])\s*`)
+	// defaultTagRe 默认识别工单号，例如 PAY-123、#456
+	defaultTagRe = map[string]string{
+		"工单":    `\b[A-Z][A-Z0-9]+-\d+\b`,
+		"Issue": `#\d{2,}\b`,
+	}
+	defaultStopwords = []string{
+		"今天", "明天", "昨天", "今日", "明日", "本周", "下周", "计划", "完成", "进行", "继续", "处理", "相关",
+		"工作", "问题", "已经", "一下", "以及", "并且", "进度", "日报", "内容", "情况", "主要", "任务", "提交",
+		"the", "and", "for", "with", "fix", "add", "update",
+	}
+)
+
+// Dictionary 项目词典：项目名 -> 别名列表，标签名 -> 正则
+type Dictionary struct {
+	Projects  map[string][]string `json:"projects"`
+	Tags      map[string]string   `json:"tags"`
+	Stopwords []string            `json:"stopwords"`
+}
+
+// Task 归并后的工作任务
+type Task struct {
+	Name      string   `json:"name"`
+	Kind      string   `json:"kind"` // project / tag / keyword
+	Days      int      `json:"days"`
+	Mentions  int      `json:"mentions"`
+	FirstSeen string   `json:"first_seen"`
+	LastSeen  string   `json:"last_seen"`
+	Entries   []string `json:"entries,omitempty"`
+}
+
+// Keyword 关键词及出现次数
+type Keyword struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// Extraction 任务提取结果
+type Extraction struct {
+	Tasks    []Task    `json:"tasks"`
+	Keywords []Keyword `json:"keywords"`
+}
+
+// Extractor 关键词、项目和任务提取器
+type Extractor struct {
+	projects  map[string][]string
+	tags      map[string]*regexp.Regexp
+	stopwords map[string]bool
+}
+
+// LoadDictionary 读取项目词典，文件不存在时返回空词典
+func LoadDictionary(path string) (*Dictionary, error) {
+	dict := &Dictionary{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return dict, nil
+		}
+		return nil, fmt.Errorf("读取项目词典失败: %w", err)
+	}
+	if err := json.Unmarshal(data, dict); err != nil {
+		return nil, fmt.Errorf("解析项目词典失败: %w", err)
+	}
+	return dict, nil
+}
+
+// NewExtractor 创建提取器，词典中的标签会覆盖同名默认标签
+func NewExtractor(dict *Dictionary) (*Extractor, error) {
+	e := &Extractor{
+		projects:  make(map[string][]string),
+		tags:      make(map[string]*regexp.Regexp),
+		stopwords: make(map[string]bool),
+	}
+
+	patterns := make(map[string]string)
+	for name, expr := range defaultTagRe {
+		patterns[name] = expr
+	}
+	for _, w := range defaultStopwords {
+		e.stopwords[strings.ToLower(w)] = true
+	}
+
+	if dict != nil {
+		for name, aliases := range dict.Projects {
+			e.projects[name] = append([]string{name}, aliases...)
+		}
+		for name, expr := range dict.Tags {
+			patterns[name] = expr
+		}
+		for _, w := range dict.Stopwords {
+			e.stopwords[strings.ToLower(w)] = true
+		}
+	}
+
+	for name, expr := range patterns {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("标签 %s 的正则无效: %w", name, err)
+		}
+		e.tags[name] = re
+	}
+
+	return e, nil
+}
+
+// SplitEntries 将一份日报拆分为独立的工作条目
+func SplitEntries(text string) []string {
+	var entries []string
+	for _, line := range entrySplitRe.Split(text, -1) {
+		for _, part := range numberingRe.Split(line, -1) {
+			part = strings.TrimFunc(part, func(r rune) bool {
+				return unicode.IsSpace(r) || unicode.IsPunct(r) && r != '#'
+			})
+			if part != "" {
+				entries = append(entries, part)
+			}
+		}
+	}
+	return entries
+}
+
+// NGrams 中英文混合分词：ASCII 按单词切分，中文按 2~4 字 n-gram 切分。
+// 用于统计关键词，与搜索索引按位置记录的二元组（search.Bigrams）用途不同
+func NGrams(text string) []string {
+	var tokens []string
+	var ascii []rune
+	var cjk []rune
+
+	flushASCII := func() {
+		if len(ascii) >= 2 {
+			tokens = append(tokens, strings.ToLower(string(ascii)))
+		}
+		ascii = ascii[:0]
+	}
+	flushCJK := func() {
+		for n := 2; n <= 4; n++ {
+			for i := 0; i+n <= len(cjk); i++ {
+				tokens = append(tokens, string(cjk[i:i+n]))
+			}
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushASCII()
+			cjk = append(cjk, r)
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-'):
+			flushCJK()
+			ascii = append(ascii, r)
+		default:
+			flushASCII()
+			flushCJK()
+		}
+	}
+	flushASCII()
+	flushCJK()
+
+	return tokens
+}
+
+// Extract 从日报中提取任务和高频关键词
+func (e *Extractor) Extract(reports []collector.Report, topN int) *Extraction {
+	tasks := make(map[string]*Task)
+	taskDays := make(map[string]map[string]bool)
+	counts := make(map[string]int)
+
+	touch := func(name, kind, day, entry string) {
+		key := kind + ":" + name
+		t, ok := tasks[key]
+		if !ok {
+			t = &Task{Name: name, Kind: kind}
+			tasks[key] = t
+			taskDays[key] = make(map[string]bool)
+		}
+		t.Mentions++
+		if len(t.Entries) < 5 {
+			t.Entries = append(t.Entries, entry)
+		}
+		if day == "" {
+			return
+		}
+		if !taskDays[key][day] {
+			taskDays[key][day] = true
+			t.Days++
+		}
+		if t.FirstSeen == "" || day < t.FirstSeen {
+			t.FirstSeen = day
+		}
+		if day > t.LastSeen {
+			t.LastSeen = day
+		}
+	}
+
+	// 第一遍统计词频，第二遍才能为未命中词典的条目选出稳定的关键词
+	type dated struct {
+		day   string
+		entry string
+	}
+	var entries []dated
+	for _, r := range reports {
+		day := ""
+		if !r.Date.IsZero() {
+			day = r.Date.Format(calendar.DateLayout)
+		}
+		for _, entry := range SplitEntries(r.Text) {
+			entries = append(entries, dated{day, entry})
+			seen := make(map[string]bool)
+			for _, tok := range NGrams(entry) {
+				if e.stopwords[tok] || isNumeric(tok) || seen[tok] {
+					continue
+				}
+				seen[tok] = true
+				counts[tok]++
+			}
+		}
+	}
+
+	for _, d := range entries {
+		lower := strings.ToLower(d.entry)
+		matched := false
+
+		for name, aliases := range e.projects {
+			for _, alias := range aliases {
+				if alias != "" && strings.Contains(lower, strings.ToLower(alias)) {
+					touch(name, "project", d.day, d.entry)
+					matched = true
+					break
+				}
+			}
+		}
+
+		for name, re := range e.tags {
+			for _, m := range re.FindAllString(d.entry, -1) {
+				touch(m, "tag:"+name, d.day, d.entry)
+				matched = true
+			}
+		}
+
+		if !matched {
+			if kw := e.bestKeyword(d.entry, counts); kw != "" {
+				touch(kw, "keyword", d.day, d.entry)
+			}
+		}
+	}
+
+	result := &Extraction{Keywords: rankKeywords(counts, topN)}
+	for _, t := range tasks {
+		result.Tasks = append(result.Tasks, *t)
+	}
+	sort.Slice(result.Tasks, func(i, j int) bool {
+		a, b := result.Tasks[i], result.Tasks[j]
+		if a.Days != b.Days {
+			return a.Days > b.Days
+		}
+		if a.Mentions != b.Mentions {
+			return a.Mentions > b.Mentions
+		}
+		return a.Name < b.Name
+	})
+	if topN > 0 && len(result.Tasks) > topN {
+		result.Tasks = result.Tasks[:topN]
+	}
+
+	return result
+}
+
+// bestKeyword 选取条目中出现频率最高、长度最长的关键词作为任务名
+func (e *Extractor) bestKeyword(entry string, counts map[string]int) string {
+	best := ""
+	for _, tok := range NGrams(entry) {
+		if e.stopwords[tok] || isNumeric(tok) {
+			continue
+		}
+		if best == "" || counts[tok] > counts[best] ||
+			counts[tok] == counts[best] && len([]rune(tok)) > len([]rune(best)) {
+			best = tok
+		}
+	}
+	if counts[best] < 2 {
+		return ""
+	}
+	return best
+}
+
+// rankKeywords 按频率排序关键词，去掉被同频更长词包含的片段
+func rankKeywords(counts map[string]int, topN int) []Keyword {
+	var words []Keyword
+	for w, n := range counts {
+		if n >= 2 {
+			words = append(words, Keyword{Word: w, Count: n})
+		}
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].Count != words[j].Count {
+			return words[i].Count > words[j].Count
+		}
+		if len(words[i].Word) != len(words[j].Word) {
+			return len(words[i].Word) > len(words[j].Word)
+		}
+		return words[i].Word < words[j].Word
+	})
+
+	var result []Keyword
+	for _, w := range words {
+		subsumed := false
+		for _, kept := range result {
+			if kept.Count == w.Count && strings.Contains(kept.Word, w.Word) {
+				subsumed = true
+				break
+			}
+		}
+		if subsumed {
+			continue
+		}
+		result = append(result, w)
+		if topN > 0 && len(result) >= topN {
+			break
+		}
+	}
+	return result
+}
+
+// TaskWeights 按任务涉及的天数分配权重，结果为整数百分比且总和为 100
+func TaskWeights(tasks []Task) []int {
+	weights := make([]int, len(tasks))
+	total := 0
+	for _, t := range tasks {
+		total += max(t.Days, 1)
+	}
+	if total == 0 {
+		return weights
+	}
+
+	// 最大余数法，保证总和恰好为 100
+	type remainder struct {
+		index int
+		frac  float64
+	}
+	var rems []remainder
+	sum := 0
+	for i, t := range tasks {
+		exact := float64(max(t.Days, 1)) * 100 / float64(total)
+		weights[i] = int(exact)
+		sum += weights[i]
+		rems = append(rems, remainder{i, exact - float64(weights[i])})
+	}
+	sort.SliceStable(rems, func(i, j int) bool { return rems[i].frac > rems[j].frac })
+	for i := 0; sum < 100; i++ {
+		weights[rems[i%len(rems)].index]++
+		sum++
+	}
+
+	return weights
+}
+
+// Format 生成可读的任务提取报告
+func (x *Extraction) Format() string {
+	var b strings.Builder
+	b.WriteString("| 序号 | 任务 | 类型 | 涉及天数 | 提及次数 | 首次 | 最近 |\n")
+	b.WriteString("|------|------|------|----------|----------|------|------|\n")
+	for i, t := range x.Tasks {
+		fmt.Fprintf(&b, "| %d | %s | %s | %d | %d | %s | %s |\n",
+			i+1, t.Name, t.Kind, t.Days, t.Mentions, t.FirstSeen, t.LastSeen)
+	}
+
+	if len(x.Keywords) > 0 {
+		var parts []string
+		for _, k := range x.Keywords {
+			parts = append(parts, fmt.Sprintf("%s(%d)", k.Word, k.Count))
+		}
+		fmt.Fprintf(&b, "\n高频关键词：%s\n", strings.Join(parts, "、"))
+	}
+
+	return b.String()
+}
+
+// isNumeric 判断是否为纯数字（日期、序号等）
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) && r != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,221 @@
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/browser"
+	"github.com/Xuzan9396/yst_go_mcp/internal/calendar"
+	"github.com/Xuzan9396/yst_go_mcp/internal/collector"
+	"github.com/Xuzan9396/yst_go_mcp/internal/fileutil"
+)
+
+// 存档目录结构：<数据目录>/archives/<时间戳>/manifest.json
+const (
+	DirName          = "archives"
+	ManifestFileName = "manifest.json"
+	dirLayout        = "20060102-150405"
+	titleMaxRunes    = 60
+)
+
+// Manifest 存档清单，记录每份报告的原文链接、文件和校验和
+type Manifest struct {
+	CreatedAt  time.Time `json:"created_at"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+	ReportType string    `json:"report_type"`
+	Range      string    `json:"range"`
+	Profile    string    `json:"profile"`
+	Items      []Item    `json:"items"`
+}
+
+// Item 一份报告的存档记录，失败时只有 Error
+type Item struct {
+	Date        string    `json:"date,omitempty"`
+	Period      string    `json:"period,omitempty"`
+	Title       string    `json:"title"`
+	Link        string    `json:"link"`
+	FinalURL    string    `json:"final_url,omitempty"`
+	PageTitle   string    `json:"page_title,omitempty"`
+	PNG         string    `json:"png,omitempty"`
+	PNGSHA256   string    `json:"png_sha256,omitempty"`
+	MHTML       string    `json:"mhtml,omitempty"`
+	MHTMLSHA256 string    `json:"mhtml_sha256,omitempty"`
+	CapturedAt  time.Time `json:"captured_at,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// Failed 返回失败的条目数
+func (m *Manifest) Failed() int {
+	n := 0
+	for _, item := range m.Items {
+		if item.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Options 存档参数
+type Options struct {
+	Root        string // 存档根目录，本次存档在其下按时间创建子目录
+	ReportType  string
+	Range       string
+	Profile     string
+	PageTimeout time.Duration // 为 0 时使用 browser.DefaultPageTimeout
+}
+
+// ProgressFunc 每存档完一份报告回调一次
+type ProgressFunc func(done, total int, item Item)
+
+// NewDir 返回按时间命名的存档目录，例如 archives/20250310-150405
+func NewDir(root string, now time.Time) string {
+	return filepath.Join(root, now.Format(dirLayout))
+}
+
+// Run 逐个打开报告链接，保存整页截图和 MHTML 快照，并在目录下写出 manifest.json。
+// 单个页面失败只记录在清单中；登录失效或被取消时停止并返回已写出的清单
+func Run(ctx context.Context, reports []collector.Report, opts Options, progress ProgressFunc) (string, *Manifest, error) {
+	dir := NewDir(opts.Root, time.Now())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return dir, nil, fmt.Errorf("创建存档目录失败: %w", err)
+	}
+
+	manifest := &Manifest{
+		CreatedAt:  time.Now(),
+		ReportType: opts.ReportType,
+		Range:      opts.Range,
+		Profile:    opts.Profile,
+	}
+
+	session, err := browser.NewSession(ctx)
+	if err != nil {
+		return dir, nil, err
+	}
+	defer session.Close()
+
+	stems := make(map[string]int)
+	var runErr error
+	for i, r := range reports {
+		if err := ctx.Err(); err != nil {
+			runErr = err
+			break
+		}
+
+		item := Item{Date: reportDate(r), Period: r.Period, Title: title(r.Text), Link: r.Link}
+		if err := capture(session, dir, fileStem(r, i, stems), &item, opts.PageTimeout); err != nil {
+			item.Error = err.Error()
+			if errors.Is(err, browser.ErrNotLoggedIn) {
+				runErr = err
+			}
+		}
+		manifest.Items = append(manifest.Items, item)
+		if err := writeManifest(dir, manifest); err != nil {
+			return dir, manifest, err
+		}
+		if progress != nil {
+			progress(i+1, len(reports), item)
+		}
+		if runErr != nil {
+			break
+		}
+	}
+
+	manifest.FinishedAt = time.Now()
+	if err := writeManifest(dir, manifest); err != nil {
+		return dir, manifest, err
+	}
+	return dir, manifest, runErr
+}
+
+// capture 抓取一份报告并写出截图和快照
+func capture(session *browser.Session, dir, stem string, item *Item, timeout time.Duration) error {
+	if item.Link == "" {
+		return fmt.Errorf("没有原文链接")
+	}
+	link, err := collector.ResolveLink(item.Link)
+	if err != nil {
+		return err
+	}
+	item.Link = link
+
+	c, err := session.Capture(link, browser.CaptureOptions{Timeout: timeout, MHTML: true})
+	if err != nil {
+		return err
+	}
+	item.FinalURL = c.URL
+	item.PageTitle = c.Title
+	item.CapturedAt = c.CapturedAt
+
+	if item.PNGSHA256, err = writeFile(dir, stem+".png", c.Image); err != nil {
+		return err
+	}
+	item.PNG = stem + ".png"
+	if item.MHTMLSHA256, err = writeFile(dir, stem+".mhtml", []byte(c.MHTML)); err != nil {
+		return err
+	}
+	item.MHTML = stem + ".mhtml"
+	return nil
+}
+
+// writeFile 写出存档文件，返回 SHA-256
+func writeFile(dir, name string, data []byte) (string, error) {
+	if err := fileutil.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+		return "", fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// writeManifest 写出清单；每完成一份都会重写，中途退出也能留下完整记录
+func writeManifest(dir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化存档清单失败: %w", err)
+	}
+	if err := fileutil.WriteFile(filepath.Join(dir, ManifestFileName), data, 0644); err != nil {
+		return fmt.Errorf("写入存档清单失败: %w", err)
+	}
+	return nil
+}
+
+// fileStem 返回存档文件名（不含扩展名）：报告日期或周期，同名时追加序号
+func fileStem(r collector.Report, index int, used map[string]int) string {
+	stem := reportDate(r)
+	if stem == "" {
+		stem = r.Period
+	}
+	if stem == "" {
+		stem = fmt.Sprintf("report-%03d", index+1)
+	}
+	used[stem]++
+	if n := used[stem]; n > 1 {
+		stem = fmt.Sprintf("%s-%d", stem, n)
+	}
+	return stem
+}
+
+// reportDate 返回报告日期，没有日期时为空
+func reportDate(r collector.Report) string {
+	if r.Date.IsZero() {
+		return ""
+	}
+	return r.Date.Format(calendar.DateLayout)
+}
+
+// title 返回报告文本的第一行，过长时截断
+func title(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	runes := []rune(strings.TrimSpace(line))
+	if len(runes) > titleMaxRunes {
+		return string(runes[:titleMaxRunes]) + "…"
+	}
+	return string(runes)
+}
@@ -83,10 +83,13 @@ func (l *Login) LaunchBrowserLogin(ctx context.Context, timeout int) error {
 		var cookieList []cookie.Cookie
 		for _, c := range cookiesData {
 			cookieList = append(cookieList, cookie.Cookie{
-				Name:   c.Name,
-				Value:  c.Value,
-				Domain: c.Domain,
-				Path:   c.Path,
+				Name:     c.Name,
+				Value:    c.Value,
+				Domain:   c.Domain,
+				Path:     c.Path,
+				Expires:  c.Expires,
+				Secure:   c.Secure,
+				HTTPOnly: c.HTTPOnly,
 			})
 		}
 
@@ -0,0 +1,199 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/cookie"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// 页面抓取参数
+const (
+	pageWidth       = 1280
+	pageHeight      = 900
+	pageSettleDelay = 800 * time.Millisecond // 页面加载后等待脚本和图片渲染
+)
+
+// DefaultPageTimeout 单个页面的默认抓取超时
+const DefaultPageTimeout = 60 * time.Second
+
+// ErrNotLoggedIn 页面跳转到了登录页
+var ErrNotLoggedIn = errors.New("登录已失效，请先使用 browser_login 重新登录")
+
+// Session 使用已保存登录态（browser_profile 和 Cookie）的无头浏览器
+type Session struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// CaptureOptions 页面抓取选项
+type CaptureOptions struct {
+	Timeout   time.Duration // 为 0 时使用 DefaultPageTimeout
+	Quality   int           // 截图质量 1-99 为 JPEG，0 或 100 为 PNG
+	MHTML     bool          // 是否同时保存 MHTML 快照
+	MaxHeight int           // 截图最大高度（CSS 像素），页面更高时只截取顶部；为 0 时截取整页
+}
+
+// Capture 一次页面抓取的结果
+type Capture struct {
+	URL        string // 最终地址（跳转之后）
+	Title      string
+	Text       string // 页面可见文本
+	Image      []byte // 整页截图，超过 MaxHeight 时只有顶部
+	MIMEType   string // image/png 或 image/jpeg
+	PageHeight int    // 页面总高度（CSS 像素）
+	Truncated  bool   // 截图是否因 MaxHeight 被截断
+	MHTML      string // 整页快照，可在浏览器中离线打开
+	CapturedAt time.Time
+}
+
+// NewSession 启动无头浏览器，复用登录时的 browser_profile，并写入保存的 Cookie
+func NewSession(ctx context.Context) (*Session, error) {
+	cookieManager := cookie.NewManager()
+
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("disable-blink-features", "AutomationControlled"),
+		chromedp.UserAgent(UserAgent),
+		chromedp.WindowSize(pageWidth, pageHeight),
+		chromedp.UserDataDir(cookieManager.GetBrowserProfileDir()),
+	)
+	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, opts...)
+	browserCtx, browserCancel := chromedp.NewContext(allocCtx, chromedp.WithLogf(log.Printf))
+	s := &Session{
+		ctx: browserCtx,
+		cancel: func() {
+			browserCancel()
+			allocCancel()
+		},
+	}
+
+	// browser_profile 可能被清理或来自 import_cookies，总是以 cookies.json 为准
+	cookies, err := cookieManager.LoadCookies()
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	if err := chromedp.Run(browserCtx, setCookies(cookies)); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("启动浏览器失败: %w", err)
+	}
+	return s, nil
+}
+
+// Close 关闭浏览器
+func (s *Session) Close() {
+	s.cancel()
+}
+
+// Capture 打开页面并抓取整页截图、可见文本，以及可选的 MHTML 快照
+func (s *Session) Capture(pageURL string, opts CaptureOptions) (*Capture, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultPageTimeout
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, opts.Timeout)
+	defer cancel()
+
+	c := &Capture{MIMEType: "image/png"}
+	if opts.Quality <= 0 || opts.Quality >= 100 {
+		opts.Quality = 100
+	} else {
+		c.MIMEType = "image/jpeg"
+	}
+	err := chromedp.Run(ctx,
+		chromedp.Navigate(pageURL),
+		chromedp.WaitReady("body"),
+		chromedp.Sleep(pageSettleDelay),
+		chromedp.Location(&c.URL),
+		chromedp.Title(&c.Title),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("打开页面失败 %s: %w", pageURL, err)
+	}
+	if strings.Contains(c.URL, "/site/login") || strings.Contains(c.URL, "accounts.google.com") {
+		return nil, ErrNotLoggedIn
+	}
+
+	err = chromedp.Run(ctx,
+		chromedp.Evaluate(`document.body ? document.body.innerText : ""`, &c.Text),
+		c.screenshot(opts.Quality, opts.MaxHeight),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			if !opts.MHTML {
+				return nil
+			}
+			var err error
+			c.MHTML, err = page.CaptureSnapshot().WithFormat(page.CaptureSnapshotFormatMhtml).Do(ctx)
+			return err
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("抓取页面失败 %s: %w", pageURL, err)
+	}
+	c.Text = strings.TrimSpace(c.Text)
+	c.CapturedAt = time.Now()
+	return c, nil
+}
+
+// screenshot 截取整页，页面高度超过 maxHeight 时只截取顶部 maxHeight 像素
+func (c *Capture) screenshot(quality, maxHeight int) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		_, _, _, _, _, content, err := page.GetLayoutMetrics().Do(ctx)
+		if err != nil {
+			return err
+		}
+		if content != nil {
+			c.PageHeight = int(math.Ceil(content.Height))
+		}
+
+		format := page.CaptureScreenshotFormatPng
+		if quality != 100 {
+			format = page.CaptureScreenshotFormatJpeg
+		}
+		capture := page.CaptureScreenshot().
+			WithCaptureBeyondViewport(true).
+			WithFromSurface(true).
+			WithFormat(format).
+			WithQuality(int64(quality))
+		if maxHeight > 0 && c.PageHeight > maxHeight {
+			c.Truncated = true
+			capture = capture.WithClip(&page.Viewport{Width: content.Width, Height: float64(maxHeight), Scale: 1})
+		}
+
+		c.Image, err = capture.Do(ctx)
+		return err
+	})
+}
+
+// setCookies 把保存的 Cookie 写入浏览器
+func setCookies(cookies []cookie.Cookie) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if len(cookies) == 0 {
+			return nil
+		}
+		params := make([]*network.CookieParam, 0, len(cookies))
+		for _, c := range cookies {
+			param := &network.CookieParam{
+				Name:     c.Name,
+				Value:    c.Value,
+				Domain:   c.Domain,
+				Path:     c.Path,
+				Secure:   c.Secure,
+				HTTPOnly: c.HTTPOnly,
+			}
+			if c.Expires > 0 {
+				expires := cdp.TimeSinceEpoch(time.Unix(int64(c.Expires), 0))
+				param.Expires = &expires
+			}
+			params = append(params, param)
+		}
+		return network.SetCookies(params).Do(ctx)
+	})
+}
@@ -0,0 +1,169 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// pdfTimeout 打印 PDF 的超时时间
+const pdfTimeout = 60 * time.Second
+
+// mmPerInch 毫米与英寸换算，PrintToPDF 的尺寸单位为英寸
+const mmPerInch = 25.4
+
+// paperSizes 支持的纸张尺寸（毫米，纵向宽 x 高）
+var paperSizes = map[string][2]float64{
+	"A3":     {297, 420},
+	"A4":     {210, 297},
+	"A5":     {148, 210},
+	"Letter": {215.9, 279.4},
+	"Legal":  {215.9, 355.6},
+}
+
+// DefaultPaper 默认纸张
+const DefaultPaper = "A4"
+
+// PaperSizes 返回支持的纸张名称
+func PaperSizes() []string {
+	names := make([]string, 0, len(paperSizes))
+	for name := range paperSizes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Margins 页边距（毫米）
+type Margins struct {
+	Top, Right, Bottom, Left float64
+}
+
+// DefaultMargins 默认页边距：上下 20mm，左右 15mm
+var DefaultMargins = Margins{Top: 20, Right: 15, Bottom: 20, Left: 15}
+
+// ParseMargins 解析页边距，格式同 CSS："上,右,下,左"、"上下,左右" 或单个值，单位毫米
+func ParseMargins(s string) (Margins, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return DefaultMargins, nil
+	}
+
+	var values []float64
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '，' || r == ' ' }) {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(part, "mm"), 64)
+		if err != nil || v < 0 {
+			return Margins{}, fmt.Errorf("页边距格式错误: %q，应为毫米数，例如 20,15,20,15", s)
+		}
+		values = append(values, v)
+	}
+
+	switch len(values) {
+	case 1:
+		return Margins{values[0], values[0], values[0], values[0]}, nil
+	case 2:
+		return Margins{values[0], values[1], values[0], values[1]}, nil
+	case 4:
+		return Margins{values[0], values[1], values[2], values[3]}, nil
+	}
+	return Margins{}, fmt.Errorf("页边距格式错误: %q，应为 1、2 或 4 个值", s)
+}
+
+// PDFOptions PDF 打印选项
+type PDFOptions struct {
+	Paper        string // A4 / A3 / A5 / Letter / Legal，为空时使用 A4
+	Landscape    bool
+	Margins      Margins
+	HeaderFooter bool   // 是否显示页眉页脚
+	Header       string // 页眉文字，为空时显示文档标题
+	Footer       string // 页脚文字，页码总是显示在右侧
+}
+
+// PrintPDF 在无头 Chrome 中渲染 HTML 并打印为 PDF
+func PrintPDF(ctx context.Context, content string, opts PDFOptions) ([]byte, error) {
+	paper := opts.Paper
+	if paper == "" {
+		paper = DefaultPaper
+	}
+	size, ok := paperSizes[paper]
+	if !ok {
+		return nil, fmt.Errorf("不支持的纸张: %s，可选 %s", paper, strings.Join(PaperSizes(), "/"))
+	}
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, chromedp.DefaultExecAllocatorOptions[:]...)
+	defer allocCancel()
+
+	browserCtx, browserCancel := chromedp.NewContext(allocCtx, chromedp.WithLogf(log.Printf))
+	defer browserCancel()
+
+	timeoutCtx, timeoutCancel := context.WithTimeout(browserCtx, pdfTimeout)
+	defer timeoutCancel()
+
+	var pdf []byte
+	err := chromedp.Run(timeoutCtx,
+		chromedp.Navigate("about:blank"),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			tree, err := page.GetFrameTree().Do(ctx)
+			if err != nil {
+				return err
+			}
+			return page.SetDocumentContent(tree.Frame.ID, content).Do(ctx)
+		}),
+		chromedp.WaitReady("body"),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			params := page.PrintToPDF().
+				WithPrintBackground(true).
+				WithLandscape(opts.Landscape).
+				WithPaperWidth(size[0] / mmPerInch).
+				WithPaperHeight(size[1] / mmPerInch).
+				WithMarginTop(opts.Margins.Top / mmPerInch).
+				WithMarginRight(opts.Margins.Right / mmPerInch).
+				WithMarginBottom(opts.Margins.Bottom / mmPerInch).
+				WithMarginLeft(opts.Margins.Left / mmPerInch).
+				WithPreferCSSPageSize(false)
+			if opts.HeaderFooter {
+				params = params.
+					WithDisplayHeaderFooter(true).
+					WithHeaderTemplate(headerTemplate(opts.Header)).
+					WithFooterTemplate(footerTemplate(opts.Footer))
+			}
+
+			data, _, err := params.Do(ctx)
+			if err != nil {
+				return err
+			}
+			pdf = data
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("打印 PDF 失败: %w", err)
+	}
+	return pdf, nil
+}
+
+// headerFooterStyle 页眉页脚默认字号为 0，必须显式指定
+const headerFooterStyle = `font-size:8px;color:#666;width:100%;padding:0 10mm;display:flex;justify-content:space-between;font-family:sans-serif;`
+
+// headerTemplate 页眉：居中文字，为空时为文档标题
+func headerTemplate(text string) string {
+	content := `<span class="title"></span>`
+	if text != "" {
+		content = `<span>` + html.EscapeString(text) + `</span>`
+	}
+	return `<div style="` + headerFooterStyle + `justify-content:center;">` + content + `</div>`
+}
+
+// footerTemplate 页脚：左侧文字，右侧页码
+func footerTemplate(text string) string {
+	return `<div style="` + headerFooterStyle + `"><span>` + html.EscapeString(text) + `</span>` +
+		`<span><span class="pageNumber"></span> / <span class="totalPages"></span></span></div>`
+}
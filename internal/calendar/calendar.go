@@ -0,0 +1,304 @@
+package calendar
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DateLayout 日期格式
+const DateLayout = "2006-01-02"
+
+// MonthLayout 月份格式
+const MonthLayout = "2006-01"
+
+// 用户自定义配置：配置目录下的 holidays.json 和 calendar/*.json
+const (
+	legacyFileName = "holidays.json"
+	overrideDir    = "calendar"
+)
+
+//go:embed data/*.json
+var bundledFS embed.FS
+
+// Calendar 工作日历（周末休息，支持法定节假日和调休上班日）
+type Calendar struct {
+	holidays map[string]string // 日期 -> 节日名称
+	workdays map[string]string // 日期 -> 调休说明
+	years    map[int]bool      // 已加载完整数据的年份
+}
+
+// Period 一组同名日期，dates 支持单日 "2025-01-01" 和区间 "2025-01-28~2025-02-04"
+type Period struct {
+	Name  string   `json:"name,omitempty"`
+	Dates []string `json:"dates"`
+}
+
+// UnmarshalJSON 兼容纯字符串写法，例如 "holidays": ["2025-01-01"]
+func (p *Period) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		p.Dates = []string{s}
+		return nil
+	}
+
+	type plain Period
+	return json.Unmarshal(data, (*plain)(p))
+}
+
+// File 节假日数据文件结构
+type File struct {
+	Year     int      `json:"year,omitempty"`
+	Holidays []Period `json:"holidays"`
+	Workdays []Period `json:"workdays"`
+}
+
+// New 创建只区分周末的工作日历
+func New() *Calendar {
+	return &Calendar{
+		holidays: make(map[string]string),
+		workdays: make(map[string]string),
+		years:    make(map[int]bool),
+	}
+}
+
+// Load 加载内置节假日数据，再叠加配置目录中的用户配置
+func Load(configDir string) (*Calendar, error) {
+	c := New()
+	if err := c.loadBundled(); err != nil {
+		return nil, err
+	}
+
+	if configDir == "" {
+		return c, nil
+	}
+
+	if err := c.LoadFile(filepath.Join(configDir, legacyFileName)); err != nil {
+		return nil, err
+	}
+
+	files, _ := filepath.Glob(filepath.Join(configDir, overrideDir, "*.json"))
+	sort.Strings(files)
+	for _, path := range files {
+		if err := c.LoadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// loadBundled 加载内置的历年节假日数据
+func (c *Calendar) loadBundled() error {
+	entries, err := bundledFS.ReadDir("data")
+	if err != nil {
+		return fmt.Errorf("读取内置节假日数据失败: %w", err)
+	}
+
+	for _, entry := range entries {
+		data, err := bundledFS.ReadFile("data/" + entry.Name())
+		if err != nil {
+			return fmt.Errorf("读取内置节假日数据失败: %w", err)
+		}
+		if err := c.apply(data, entry.Name()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadFile 从 JSON 文件加载节假日配置，文件不存在时忽略
+func (c *Calendar) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("读取节假日配置失败: %w", err)
+	}
+
+	return c.apply(data, path)
+}
+
+// apply 解析并应用一份节假日数据
+func (c *Calendar) apply(data []byte, source string) error {
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("解析节假日配置 %s 失败: %w", source, err)
+	}
+
+	for _, p := range f.Holidays {
+		if err := c.addPeriod(c.holidays, c.workdays, p); err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+	}
+	for _, p := range f.Workdays {
+		if err := c.addPeriod(c.workdays, c.holidays, p); err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+	}
+
+	if f.Year > 0 {
+		c.years[f.Year] = true
+	}
+
+	return nil
+}
+
+// addPeriod 将一组日期写入 target，并从 other 中移除（后加载的配置优先）
+func (c *Calendar) addPeriod(target, other map[string]string, p Period) error {
+	for _, spec := range p.Dates {
+		days, err := expand(spec)
+		if err != nil {
+			return err
+		}
+		for _, key := range days {
+			target[key] = p.Name
+			delete(other, key)
+		}
+	}
+	return nil
+}
+
+// AddHolidays 添加节假日（YYYY-MM-DD 或 YYYY-MM-DD~YYYY-MM-DD）
+func (c *Calendar) AddHolidays(dates ...string) error {
+	return c.addPeriod(c.holidays, c.workdays, Period{Name: "自定义假日", Dates: dates})
+}
+
+// AddWorkdays 添加调休上班日（YYYY-MM-DD 或 YYYY-MM-DD~YYYY-MM-DD）
+func (c *Calendar) AddWorkdays(dates ...string) error {
+	return c.addPeriod(c.workdays, c.holidays, Period{Name: "自定义上班日", Dates: dates})
+}
+
+// IsWorkday 判断是否为工作日
+func (c *Calendar) IsWorkday(date time.Time) bool {
+	key := date.Format(DateLayout)
+	if _, ok := c.workdays[key]; ok {
+		return true
+	}
+	if _, ok := c.holidays[key]; ok {
+		return false
+	}
+	weekday := date.Weekday()
+	return weekday != time.Saturday && weekday != time.Sunday
+}
+
+// HolidayName 返回节假日名称，不是节假日返回空字符串
+func (c *Calendar) HolidayName(date time.Time) string {
+	return c.holidays[date.Format(DateLayout)]
+}
+
+// HasYear 判断是否有该年份的节假日数据（没有时只能按周末计算）
+func (c *Calendar) HasYear(year int) bool {
+	return c.years[year]
+}
+
+// Workdays 返回 [start, end] 闭区间内的所有工作日
+func (c *Calendar) Workdays(start, end time.Time) []time.Time {
+	var days []time.Time
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if c.IsWorkday(d) {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// PrevWorkday 返回 date 之前最近的一个工作日（最多回溯 30 天）
+func (c *Calendar) PrevWorkday(date time.Time) time.Time {
+	d := date.AddDate(0, 0, -1)
+	for i := 0; i < 30 && !c.IsWorkday(d); i++ {
+		d = d.AddDate(0, 0, -1)
+	}
+	return d
+}
+
+// NextWorkday 返回 date 之后最近的一个工作日（最多向后 30 天）
+func (c *Calendar) NextWorkday(date time.Time) time.Time {
+	d := date.AddDate(0, 0, 1)
+	for i := 0; i < 30 && !c.IsWorkday(d); i++ {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// WorkdaysIn 返回指定月份（YYYY-MM）的所有工作日
+func (c *Calendar) WorkdaysIn(month string) ([]time.Time, error) {
+	start, end, err := MonthBounds(month)
+	if err != nil {
+		return nil, err
+	}
+	return c.Workdays(start, end), nil
+}
+
+// MissingYears 返回区间内缺少节假日数据的年份
+func (c *Calendar) MissingYears(start, end time.Time) []int {
+	var years []int
+	for y := start.Year(); y <= end.Year(); y++ {
+		if !c.years[y] {
+			years = append(years, y)
+		}
+	}
+	return years
+}
+
+// MonthBounds 返回月份（YYYY-MM）的第一天和最后一天
+func MonthBounds(month string) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(MonthLayout, month, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("月份格式错误 %q，应为 YYYY-MM", month)
+	}
+	return start, start.AddDate(0, 1, -1), nil
+}
+
+// SplitDates 解析逗号分隔的日期列表
+func SplitDates(s string) []string {
+	var dates []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '，' || r == ' ' || r == '\n'
+	}) {
+		dates = append(dates, strings.TrimSpace(part))
+	}
+	return dates
+}
+
+// expand 展开单日或日期区间
+func expand(spec string) ([]string, error) {
+	from, to, isRange := strings.Cut(spec, "~")
+	start, err := parseDate(from)
+	if err != nil {
+		return nil, err
+	}
+	if !isRange {
+		return []string{start.Format(DateLayout)}, nil
+	}
+
+	end, err := parseDate(to)
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("日期区间 %q 起止颠倒", spec)
+	}
+
+	var days []string
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format(DateLayout))
+	}
+	return days, nil
+}
+
+// parseDate 校验并解析日期字符串
+func parseDate(d string) (time.Time, error) {
+	t, err := time.ParseInLocation(DateLayout, strings.TrimSpace(d), time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("日期格式错误 %q，应为 YYYY-MM-DD", d)
+	}
+	return t, nil
+}
@@ -0,0 +1,156 @@
+package calendar
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.ParseInLocation(DateLayout, s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestIsWorkday(t *testing.T) {
+	c, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		date string
+		want bool
+	}{
+		{"2025-03-10", true},  // 普通周一
+		{"2025-03-15", false}, // 普通周六
+		{"2025-01-01", false}, // 元旦
+		{"2025-01-31", false}, // 春节假期中的周五
+		{"2025-01-26", true},  // 春节调休的周日
+		{"2025-02-08", true},  // 春节调休的周六
+		{"2025-10-08", false}, // 国庆节
+		{"2025-10-11", true},  // 国庆节调休的周六
+	}
+
+	for _, tt := range tests {
+		if got := c.IsWorkday(date(tt.date)); got != tt.want {
+			t.Errorf("IsWorkday(%s) = %v，期望 %v", tt.date, got, tt.want)
+		}
+	}
+
+	if name := c.HolidayName(date("2025-01-01")); name != "元旦" {
+		t.Errorf("HolidayName(2025-01-01) = %q，期望 元旦", name)
+	}
+	if !c.HasYear(2025) || c.HasYear(1999) {
+		t.Error("HasYear 应只包含内置数据的年份")
+	}
+}
+
+func TestLoadOverrides(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "holidays.json"), `{"holidays": ["2025-03-10"], "workdays": [{"name": "补班", "dates": ["2025-03-15"]}]}`)
+	writeFile(t, filepath.Join(dir, "calendar", "b.json"), `{"workdays": ["2025-03-10"]}`)
+	writeFile(t, filepath.Join(dir, "calendar", "a.json"), `{"holidays": [{"name": "年假", "dates": ["2025-03-11~2025-03-12"]}]}`)
+
+	c, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		date string
+		want bool
+	}{
+		{"2025-03-10", true},  // holidays.json 设为假日，calendar/b.json 又改回上班
+		{"2025-03-11", false}, // calendar/a.json 年假
+		{"2025-03-12", false},
+		{"2025-03-13", true},
+		{"2025-03-15", true},  // 周六补班
+		{"2025-01-01", false}, // 内置数据仍然生效
+	}
+	for _, tt := range tests {
+		if got := c.IsWorkday(date(tt.date)); got != tt.want {
+			t.Errorf("IsWorkday(%s) = %v，期望 %v", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"JSON 格式错误", `{"holidays": [`, "解析节假日配置"},
+		{"日期格式错误", `{"holidays": ["2025/03/10"]}`, "日期格式错误"},
+		{"区间颠倒", `{"holidays": ["2025-03-12~2025-03-10"]}`, "起止颠倒"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, "holidays.json"), tt.content)
+			_, err := Load(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() 错误 = %v，期望包含 %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkdays(t *testing.T) {
+	c := New()
+	if err := c.AddHolidays("2025-03-12"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddWorkdays("2025-03-15"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, d := range c.Workdays(date("2025-03-10"), date("2025-03-16")) {
+		got = append(got, d.Format(DateLayout))
+	}
+	if want := "2025-03-10,2025-03-11,2025-03-13,2025-03-14,2025-03-15"; strings.Join(got, ",") != want {
+		t.Errorf("Workdays() = %v，期望 %s", got, want)
+	}
+
+	tests := []struct {
+		name string
+		fn   func(time.Time) time.Time
+		from string
+		want string
+	}{
+		{"PrevWorkday 跳过假日", c.PrevWorkday, "2025-03-13", "2025-03-11"},
+		{"PrevWorkday 跳过周末", c.PrevWorkday, "2025-03-10", "2025-03-07"},
+		{"NextWorkday 跳过假日", c.NextWorkday, "2025-03-11", "2025-03-13"},
+		{"NextWorkday 遇到补班", c.NextWorkday, "2025-03-14", "2025-03-15"},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(date(tt.from)).Format(DateLayout); got != tt.want {
+			t.Errorf("%s(%s) = %s，期望 %s", tt.name, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestSplitDates(t *testing.T) {
+	got := SplitDates("2025-03-10, 2025-03-11，2025-03-12\n2025-03-13~2025-03-14")
+	want := []string{"2025-03-10", "2025-03-11", "2025-03-12", "2025-03-13~2025-03-14"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("SplitDates() = %q，期望 %q", got, want)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,382 @@
+package collector
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/Xuzan9396/yst_go_mcp/internal/fileutil"
+)
+
+// AssetsDirSuffix 附件目录后缀：日报详情.md 的附件保存在同目录的 日报详情_assets/ 下
+const AssetsDirSuffix = "_assets"
+
+// maxAssetSize 单个附件的大小上限
+const maxAssetSize = 50 << 20
+
+// detailSelectors 详情页正文区域，按顺序取第一个存在的
+var detailSelectors = []string{".detail-view", ".report-content", ".box-body", ".panel-body", "article", "main", ".content"}
+
+// attachmentExts 视为附件的链接扩展名
+var attachmentExts = map[string]bool{
+	".pdf": true, ".doc": true, ".docx": true, ".xls": true, ".xlsx": true, ".ppt": true, ".pptx": true,
+	".zip": true, ".rar": true, ".7z": true, ".txt": true, ".csv": true, ".md": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true, ".bmp": true, ".svg": true,
+	".mp4": true, ".mov": true,
+}
+
+// attachmentPathRe 路径中带这些关键字的链接也视为附件
+var attachmentPathRe = regexp.MustCompile(`(?i)/(upload|uploads|attachment|attachments|download|file|files)/`)
+
+// dataURIRe 粘贴到正文中的 base64 图片
+var dataURIRe = regexp.MustCompile(`^data:(image/[a-zA-Z0-9.+-]+);base64,(.+)$`)
+
+// Attachment 报告详情页中的附件或图片
+type Attachment struct {
+	Name  string
+	URL   string // 原始地址，粘贴的 base64 图片为 data: 地址
+	Image bool
+	Local string // 本地副本，写入 Markdown 时为相对 MD 文件所在目录的路径
+}
+
+// SetDownloadAssets 设置 Collect 时是否读取详情页并下载附件和图片
+func (c *Collector) SetDownloadAssets(download bool) {
+	c.downloadAssets = download
+}
+
+// AssetsDir 返回导出文件对应的附件目录
+func AssetsDir(outputFile string) string {
+	return strings.TrimSuffix(outputFile, filepath.Ext(outputFile)) + AssetsDirSuffix
+}
+
+// FetchAttachments 读取报告详情页，返回正文中的附件链接和图片
+func (c *Collector) FetchAttachments(link string) ([]Attachment, error) {
+	pageURL, err := ResolveLink(link)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := c.fetchDocument(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("读取详情页失败: %w", err)
+	}
+	return parseAttachments(doc), nil
+}
+
+// parseAttachments 解析详情页正文区域中的图片和附件链接，按地址去重
+func parseAttachments(doc *goquery.Document) []Attachment {
+	content := doc.Find("body")
+	for _, sel := range detailSelectors {
+		if s := doc.Find(sel).First(); s.Length() > 0 {
+			content = s
+			break
+		}
+	}
+
+	var attachments []Attachment
+	seen := make(map[string]bool)
+	add := func(a Attachment) {
+		if a.URL == "" || seen[a.URL] {
+			return
+		}
+		seen[a.URL] = true
+		attachments = append(attachments, a)
+	}
+
+	content.Find("img[src]").Each(func(i int, s *goquery.Selection) {
+		if s.Closest("header, nav, aside, .navbar, .sidebar, .main-header, .user-panel").Length() > 0 {
+			return
+		}
+		src, _ := s.Attr("src")
+		alt, _ := s.Attr("alt")
+		if u := absoluteURL(doc, src); u != "" {
+			add(Attachment{Name: strings.TrimSpace(alt), URL: u, Image: true})
+		}
+	})
+
+	content.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		u := absoluteURL(doc, href)
+		if u == "" || strings.HasPrefix(u, "data:") {
+			return
+		}
+		_, hasDownload := s.Attr("download")
+		parsed, _ := url.Parse(u)
+		ext := strings.ToLower(path.Ext(parsed.Path))
+		if !hasDownload && !attachmentExts[ext] && !attachmentPathRe.MatchString(parsed.Path) {
+			return
+		}
+		add(Attachment{Name: strings.TrimSpace(s.Text()), URL: u, Image: isImageExt(ext)})
+	})
+	return attachments
+}
+
+// absoluteURL 把页面中的相对地址转换为完整地址，忽略锚点和脚本链接
+func absoluteURL(doc *goquery.Document, ref string) string {
+	ref = strings.TrimSpace(ref)
+	switch {
+	case ref == "", strings.HasPrefix(ref, "#"), strings.HasPrefix(ref, "javascript:"), strings.HasPrefix(ref, "mailto:"):
+		return ""
+	case strings.HasPrefix(ref, "data:"):
+		return ref
+	}
+	base := doc.Url
+	if base == nil {
+		base, _ = url.Parse(BaseURL)
+	}
+	u, err := base.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+// assetStore 附件目录：按内容哈希命名，相同内容只保存一份
+type assetStore struct {
+	dir   string
+	byURL map[string]string // 原始地址 -> 本地文件名，同一地址只下载一次
+}
+
+// localizeAssets 读取每份报告的详情页，把附件和图片下载到 dir，并记录相对 dir 所在目录的路径。
+// 单个报告或附件失败只记录日志并保留原始地址，不影响导出，返回新写入目录的文件数（不含重复和已存在的文件）
+func (c *Collector) localizeAssets(allReports map[string][]Report, dir string) int {
+	store := &assetStore{dir: dir, byURL: make(map[string]string)}
+	rel := filepath.Base(dir)
+
+	count := 0
+	for key := range allReports {
+		for i := range allReports[key] {
+			r := &allReports[key][i]
+			if r.Link == "" || c.ctx.Err() != nil {
+				continue
+			}
+			attachments, err := c.FetchAttachments(r.Link)
+			if err != nil {
+				log.Printf("⚠️ %s：%v", r.Link, err)
+				continue
+			}
+			// 同一份报告中内容相同的附件只列一次
+			var kept []Attachment
+			listed := make(map[string]bool)
+			for _, a := range attachments {
+				name, created, err := c.saveAsset(store, &a)
+				if err != nil {
+					log.Printf("⚠️ 下载附件失败 %s：%v", shortURL(a.URL), err)
+					kept = append(kept, a)
+					continue
+				}
+				if created {
+					count++
+				}
+				if listed[name] {
+					continue
+				}
+				listed[name] = true
+				a.Local = filepath.ToSlash(filepath.Join(rel, name))
+				kept = append(kept, a)
+			}
+			r.Attachments = kept
+		}
+	}
+	return count
+}
+
+// saveAsset 下载附件并按 SHA-256 保存，返回文件名，以及是否新写入了文件（相同内容已存在时为 false）
+func (c *Collector) saveAsset(store *assetStore, a *Attachment) (string, bool, error) {
+	if name, ok := store.byURL[a.URL]; ok {
+		return name, false, nil
+	}
+
+	data, contentType, filename, err := c.downloadAsset(a.URL)
+	if err != nil {
+		return "", false, err
+	}
+	if a.Name == "" {
+		a.Name = filename
+	}
+	if u, err := url.Parse(a.URL); a.Name == "" && err == nil && u.Scheme != "data" {
+		a.Name = path.Base(u.Path)
+	}
+	if strings.HasPrefix(contentType, "image/") {
+		a.Image = true
+	}
+
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:])[:16] + assetExt(a.URL, filename, contentType)
+	target := filepath.Join(store.dir, name)
+	created := false
+	// 原子写入：文件名即内容哈希，已存在的文件会被直接复用，不能留下写了一半的文件
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		if err := fileutil.WriteFile(target, data, 0644); err != nil {
+			return "", false, fmt.Errorf("保存附件失败: %w", err)
+		}
+		created = true
+	}
+	store.byURL[a.URL] = name
+	return name, created, nil
+}
+
+// downloadAsset 用已登录的客户端下载附件，返回内容、类型和服务器给出的文件名
+func (c *Collector) downloadAsset(assetURL string) ([]byte, string, string, error) {
+	if m := dataURIRe.FindStringSubmatch(assetURL); m != nil {
+		data, err := base64.StdEncoding.DecodeString(m[2])
+		if err != nil {
+			return nil, "", "", fmt.Errorf("解析内嵌图片失败: %w", err)
+		}
+		return data, m[1], "", nil
+	}
+
+	req, err := http.NewRequestWithContext(c.ctx, "GET", assetURL, nil)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("创建请求失败: %w", err)
+	}
+	setBrowserHeaders(req)
+	req.Header.Set("Accept", "*/*")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", "", fmt.Errorf("HTTP 状态码: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAssetSize+1))
+	if err != nil {
+		return nil, "", "", fmt.Errorf("读取失败: %w", err)
+	}
+	if len(data) > maxAssetSize {
+		return nil, "", "", fmt.Errorf("超过 %d MB", maxAssetSize>>20)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if strings.HasPrefix(contentType, "text/html") {
+		return nil, "", "", fmt.Errorf("返回的是网页，可能需要重新登录")
+	}
+	var filename string
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		filename = params["filename"]
+	}
+	return data, contentType, filename, nil
+}
+
+// assetExt 依次从地址、服务器文件名和内容类型推断扩展名
+func assetExt(assetURL, filename, contentType string) string {
+	if !strings.HasPrefix(assetURL, "data:") {
+		if u, err := url.Parse(assetURL); err == nil {
+			if ext := strings.ToLower(path.Ext(u.Path)); ext != "" && len(ext) <= 6 {
+				return ext
+			}
+		}
+	}
+	if ext := strings.ToLower(filepath.Ext(filename)); ext != "" {
+		return ext
+	}
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/svg+xml":
+		return ".svg"
+	}
+	if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// isImageExt 判断扩展名是否为图片
+func isImageExt(ext string) bool {
+	switch ext {
+	case ".png", ".jpg", ".jpeg", ".gif", ".webp", ".bmp", ".svg":
+		return true
+	}
+	return false
+}
+
+// shortURL 日志中缩短 data: 地址
+func shortURL(u string) string {
+	if strings.HasPrefix(u, "data:") && len(u) > 40 {
+		return u[:40] + "…"
+	}
+	return u
+}
+
+// writeAttachments 在 Markdown 中输出附件列表：图片内嵌显示，其余为链接；没有本地副本的使用原始地址
+func writeAttachments(w io.Writer, attachments []Attachment) {
+	if len(attachments) == 0 {
+		return
+	}
+	fmt.Fprint(w, "附件：\n\n")
+	for _, a := range attachments {
+		target := a.Local
+		if target == "" {
+			if strings.HasPrefix(a.URL, "data:") {
+				continue
+			}
+			target = a.URL
+		}
+		name := a.Name
+		if name == "" {
+			name = path.Base(target)
+		}
+		prefix := ""
+		if a.Image {
+			prefix = "!"
+		}
+		fmt.Fprintf(w, "- %s[%s](%s)\n", prefix, escapeLinkText(name), markdownURL(target))
+	}
+	fmt.Fprintln(w)
+}
+
+// attachmentLineRe 解析 writeAttachments 输出的附件行
+var attachmentLineRe = regexp.MustCompile(`^- (!?)\[(.*)\]\(<?([^<>]*?)>?\)$`)
+
+// parseAttachmentLine 解析一行附件，失败返回 false
+func parseAttachmentLine(line string) (Attachment, bool) {
+	m := attachmentLineRe.FindStringSubmatch(line)
+	if m == nil {
+		return Attachment{}, false
+	}
+	a := Attachment{Name: strings.NewReplacer(`\[`, "[", `\]`, "]").Replace(m[2]), Image: m[1] == "!"}
+	if u, err := url.Parse(m[3]); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		a.URL = m[3]
+	} else {
+		a.Local = m[3]
+	}
+	return a, true
+}
+
+// escapeLinkText 转义链接文字中的方括号
+func escapeLinkText(s string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`, "\n", " ").Replace(s)
+}
+
+// markdownURL 地址中有空格或括号时用尖括号包起来
+func markdownURL(u string) string {
+	if strings.ContainsAny(u, " ()") {
+		return "<" + u + ">"
+	}
+	return u
+}
+
+// rewriteAssetLinks 把正文中的附件原始地址替换为本地副本
+func rewriteAssetLinks(text string, attachments []Attachment) string {
+	for _, a := range attachments {
+		if a.Local != "" && a.URL != "" {
+			text = strings.ReplaceAll(text, a.URL, a.Local)
+		}
+	}
+	return text
+}
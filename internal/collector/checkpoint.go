@@ -0,0 +1,127 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/daterange"
+	"github.com/Xuzan9396/yst_go_mcp/internal/fileutil"
+	"github.com/Xuzan9396/yst_go_mcp/internal/paths"
+)
+
+// CheckpointDirName 状态目录下保存采集检查点的子目录
+const CheckpointDirName = "checkpoints"
+
+// Checkpoint 一次采集的进度：每完成一个周期写入一次，全部完成并导出后删除
+type Checkpoint struct {
+	path       string
+	ReportType string              `json:"report_type"`
+	Range      string              `json:"range"`
+	Done       map[string][]Report `json:"done"`
+	Failed     map[string]string   `json:"failed,omitempty"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// SetResume 设置是否从上次的检查点继续采集
+func (c *Collector) SetResume(resume bool) {
+	c.resume = resume
+}
+
+// SetAllowPartial 设置部分周期采集失败时是否仍导出已完成部分
+func (c *Collector) SetAllowPartial(partial bool) {
+	c.allowPartial = partial
+}
+
+// openCheckpoint 打开采集范围对应的检查点：resume 时加载已有进度，否则重新开始
+func (c *Collector) openCheckpoint(r daterange.Range) (*Checkpoint, error) {
+	name := fmt.Sprintf("%s_%s_%s.json", c.reportType.Name,
+		r.Start.Format(daterange.DateLayout), r.End.Format(daterange.DateLayout))
+	cp := &Checkpoint{
+		path:       filepath.Join(paths.State(), CheckpointDirName, name),
+		ReportType: c.reportType.Name,
+		Range:      r.String(),
+		Done:       make(map[string][]Report),
+		Failed:     make(map[string]string),
+	}
+
+	if !c.resume {
+		return cp, nil
+	}
+
+	data, err := os.ReadFile(cp.path)
+	if os.IsNotExist(err) {
+		log.Printf("没有找到检查点，从头开始采集: %s", cp.path)
+		return cp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取检查点失败: %w", err)
+	}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("解析检查点失败: %w", err)
+	}
+	if cp.Done == nil {
+		cp.Done = make(map[string][]Report)
+	}
+	cp.Failed = make(map[string]string)
+	log.Printf("✓ 从检查点继续，已完成 %d 个周期", len(cp.Done))
+	return cp, nil
+}
+
+// lookup 返回检查点中已完成周期的报告，检查点为空时返回 false
+func (cp *Checkpoint) lookup(key string) ([]Report, bool) {
+	if cp == nil {
+		return nil, false
+	}
+	reports, ok := cp.Done[key]
+	return reports, ok
+}
+
+// complete 记录一个已完成的周期并保存
+func (cp *Checkpoint) complete(key string, reports []Report) {
+	cp.Done[key] = reports
+	delete(cp.Failed, key)
+	cp.save()
+}
+
+// fail 记录一个失败的周期并保存
+func (cp *Checkpoint) fail(key string, err error) {
+	cp.Failed[key] = err.Error()
+	cp.save()
+}
+
+// Pending 返回尚未完成的周期
+func (cp *Checkpoint) Pending(keys []string) []string {
+	var pending []string
+	for _, key := range keys {
+		if _, ok := cp.Done[key]; !ok {
+			pending = append(pending, key)
+		}
+	}
+	sort.Strings(pending)
+	return pending
+}
+
+// save 写入检查点文件，失败只记录日志
+func (cp *Checkpoint) save() {
+	cp.UpdatedAt = time.Now()
+	data, err := json.Marshal(cp)
+	if err != nil {
+		log.Printf("序列化检查点失败: %v", err)
+		return
+	}
+	if err := fileutil.WriteFile(cp.path, data, 0644); err != nil {
+		log.Printf("保存检查点失败: %v", err)
+	}
+}
+
+// remove 删除检查点文件
+func (cp *Checkpoint) remove() {
+	if err := os.Remove(cp.path); err != nil && !os.IsNotExist(err) {
+		log.Printf("删除检查点失败: %v", err)
+	}
+}
@@ -1,48 +1,146 @@
 package collector
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/Xuzan9396/yst_go_mcp/internal/cookie"
+	"github.com/Xuzan9396/yst_go_mcp/internal/daterange"
+	"github.com/Xuzan9396/yst_go_mcp/internal/fileutil"
+	"github.com/Xuzan9396/yst_go_mcp/internal/htmlmd"
+	"github.com/Xuzan9396/yst_go_mcp/internal/paths"
 )
 
 const (
-	BaseURL       = "https://kpi.drojian.dev"
+	SiteDomain    = "kpi.drojian.dev"
+	BaseURL       = "https://" + SiteDomain
 	ReportListURL = BaseURL + "/report/report-daily/my-list"
 	UserAgent     = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 Safari/537.36"
 )
 
 // Collector 日报采集器
 type Collector struct {
-	client        *http.Client
-	cookieManager *cookie.Manager
+	client         *http.Client
+	cookieManager  *cookie.Manager
+	reportType     *ReportType
+	collected      []Report // 最近一次 Collect 采集到的报告
+	ctx            context.Context
+	progress       ProgressFunc
+	checkpoint     *Checkpoint // Collect 期间记录每个周期的进度
+	resume         bool
+	allowPartial   bool
+	downloadAssets bool             // 读取详情页并下载附件和图片
+	output         fileutil.Options // 导出文件的冲突处理和备份
 }
 
+// ProgressFunc 每采集完一个周期回调一次：done/total 为已完成/总周期数
+type ProgressFunc func(done, total int, key string, reports []Report)
+
 // Report 日报信息
 type Report struct {
-	Text string
-	Link string
+	Text        string
+	Link        string
+	Date        time.Time    // 日报所属日期（从列表文本解析，解析失败为零值）
+	SubmittedAt time.Time    // 提交时间（列表文本中带时分的时间，没有则为零值）
+	Period      string       // 所属周期：日报为日期，周报为 ISO 周（2025-W03），月报为月份
+	Markdown    string       // 正文 Markdown，保留列表、表格、链接等结构；Text 为对应的纯文本
+	Attachments []Attachment // 详情页中的附件和图片（开启下载附件时才有）
+}
+
+var (
+	dateRe     = regexp.MustCompile(`(\d{4})\s*[-/.年]\s*(\d{1,2})\s*[-/.月]\s*(\d{1,2})\s*日?`)
+	dateTimeRe = regexp.MustCompile(`(\d{4})[-/.](\d{1,2})[-/.](\d{1,2})[ T]+(\d{1,2}):(\d{2})(?::(\d{2}))?`)
+)
+
+// ParseReportDates 从日报列表文本中解析日报日期和提交时间
+func ParseReportDates(text string) (date, submittedAt time.Time) {
+	if m := dateRe.FindStringSubmatch(text); m != nil {
+		date = buildTime(m[1], m[2], m[3], "0", "0", "0")
+	}
+	if m := dateTimeRe.FindStringSubmatch(text); m != nil {
+		submittedAt = buildTime(m[1], m[2], m[3], m[4], m[5], m[6])
+	}
+	return date, submittedAt
+}
+
+// buildTime 由正则分组构造本地时间
+func buildTime(parts ...string) time.Time {
+	var n [6]int
+	for i, p := range parts {
+		n[i], _ = strconv.Atoi(p)
+	}
+	t := time.Date(n[0], time.Month(n[1]), n[2], n[3], n[4], n[5], 0, time.Local)
+	if t.Month() != time.Month(n[1]) {
+		return time.Time{}
+	}
+	return t
 }
 
 // NewCollector 创建日报采集器
 func NewCollector() *Collector {
 	jar, _ := cookiejar.New(nil)
+	cookieManager := cookie.NewManager()
 	return &Collector{
 		client: &http.Client{
 			Jar:     jar,
 			Timeout: 30 * time.Second,
 		},
-		cookieManager: cookie.NewManager(),
+		cookieManager: cookieManager,
+		reportType:    reportTypes[TypeDaily],
+		ctx:           context.Background(),
+		output: fileutil.Options{
+			OnConflict: fileutil.ConflictOverwrite,
+			BackupDir:  filepath.Join(paths.Data(), fileutil.VersionsDirName),
+		},
+	}
+}
+
+// SetOnConflict 设置导出文件已存在时的处理方式（overwrite / version / fail）
+func (c *Collector) SetOnConflict(mode string) error {
+	mode, err := fileutil.ParseConflict(mode)
+	if err != nil {
+		return err
+	}
+	c.output.OnConflict = mode
+	return nil
+}
+
+// SetContext 设置请求使用的上下文，取消后正在进行的采集会尽快停止
+func (c *Collector) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// OnProgress 设置采集进度回调
+func (c *Collector) OnProgress(fn ProgressFunc) {
+	c.progress = fn
+}
+
+// SetReportType 设置采集的报告类型（daily / weekly / monthly）
+func (c *Collector) SetReportType(name string) error {
+	rt, err := GetReportType(name)
+	if err != nil {
+		return err
 	}
+	c.reportType = rt
+	return nil
+}
+
+// ReportType 返回当前采集的报告类型
+func (c *Collector) ReportType() *ReportType {
+	return c.reportType
 }
 
 // LoadSavedCookies 加载保存的 Cookies
@@ -56,6 +154,12 @@ func (c *Collector) LoadSavedCookies() error {
 		return fmt.Errorf("没有保存的 Cookie")
 	}
 
+	c.ApplyCookies(cookies)
+	return nil
+}
+
+// ApplyCookies 将 Cookies 设置到 HTTP 客户端（不落盘）
+func (c *Collector) ApplyCookies(cookies []cookie.Cookie) {
 	// 转换为 http.Cookie 格式
 	baseURL, _ := url.Parse(BaseURL)
 	var httpCookies []*http.Cookie
@@ -69,7 +173,6 @@ func (c *Collector) LoadSavedCookies() error {
 	}
 
 	c.client.Jar.SetCookies(baseURL, httpCookies)
-	return nil
 }
 
 // CheckLoginStatus 检查登录状态
@@ -84,18 +187,25 @@ func (c *Collector) CheckLoginStatus() bool {
 	return resp.StatusCode == http.StatusOK && !strings.Contains(resp.Request.URL.String(), "login")
 }
 
-// FetchMonthReports 获取指定月份的日报列表
-func (c *Collector) FetchMonthReports(month string) ([]Report, error) {
-	reportURL := fmt.Sprintf("%s?month=%s", ReportListURL, month)
+// FetchReports 获取指定周期（按报告类型为月份或年份）的报告列表
+func (c *Collector) FetchReports(query string) ([]Report, error) {
+	rt := c.reportType
+	doc, err := c.fetchDocument(rt.ListPageURL(query))
+	if err != nil {
+		return nil, err
+	}
 
-	req, err := http.NewRequest("GET", reportURL, nil)
+	return rt.parseList(doc), nil
+}
+
+// fetchDocument 以浏览器请求头获取页面并解析 HTML
+func (c *Collector) fetchDocument(pageURL string) (*goquery.Document, error) {
+	req, err := http.NewRequestWithContext(c.ctx, "GET", pageURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
 
-	req.Header.Set("User-Agent", UserAgent)
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "zh-CN,zh-TW;q=0.9,zh;q=0.8,en;q=0.7")
+	setBrowserHeaders(req)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -112,47 +222,140 @@ func (c *Collector) FetchMonthReports(month string) ([]Report, error) {
 	if err != nil {
 		return nil, fmt.Errorf("解析 HTML 失败: %w", err)
 	}
+	doc.Url = resp.Request.URL
+
+	return doc, nil
+}
+
+// setBrowserHeaders 设置与浏览器一致的请求头
+func setBrowserHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	req.Header.Set("Accept-Language", "zh-CN,zh-TW;q=0.9,zh;q=0.8,en;q=0.7")
+}
 
-	var reports []Report
-	doc.Find("#report_list li").Each(func(i int, s *goquery.Selection) {
-		text := strings.TrimSpace(s.Text())
-		link, _ := s.Find("a").Attr("href")
+// GenerateMonthRange 生成月份范围列表，兼容 2025-3、2025/03、202503、2025年3月 等写法
+func (c *Collector) GenerateMonthRange(startMonth, endMonth string) ([]string, error) {
+	return daterange.Months(startMonth, endMonth)
+}
 
-		if text != "" {
-			reports = append(reports, Report{
-				Text: text,
-				Link: link,
-			})
+// EnsureLogin 加载已保存的 Cookie 并检查登录状态
+func (c *Collector) EnsureLogin() error {
+	// 加载已保存的 Cookie
+	if c.cookieManager.HasCookies() {
+		if err := c.LoadSavedCookies(); err != nil {
+			return fmt.Errorf("加载 Cookie 失败: %w", err)
 		}
-	})
+	}
 
-	return reports, nil
+	// 检查登录状态
+	if !c.CheckLoginStatus() {
+		return fmt.Errorf("未登录或登录已过期，请先使用 browser_login 工具登录")
+	}
+
+	return nil
 }
 
-// GenerateMonthRange 生成月份范围列表
-func (c *Collector) GenerateMonthRange(startMonth, endMonth string) ([]string, error) {
-	start, err := time.Parse("2006-01", startMonth)
-	if err != nil {
-		return nil, fmt.Errorf("起始月份格式错误: %w", err)
+// FetchError 部分周期的列表页采集失败；返回的结果中不包含这些周期，不能当作“没有报告”
+type FetchError struct {
+	Failed []string         // 失败的查询周期
+	Errs   map[string]error // 每个周期的错误
+}
+
+func (e *FetchError) Error() string {
+	first := e.Errs[e.Failed[0]]
+	return fmt.Sprintf("%d 个周期采集失败（%s）: %v", len(e.Failed), strings.Join(e.Failed, "、"), first)
+}
+
+// CollectReports 加载 Cookie、检查登录状态并采集指定月份范围的报告
+// 返回列表页的查询周期（日报/周报为月份，月报为年份）及各周期的报告。
+// 有周期失败时：使用检查点（Collect）时由检查点记录，否则返回已采集部分和 *FetchError，
+// 需要部分结果的调用方用 errors.As 取出失败的周期
+func (c *Collector) CollectReports(startMonth, endMonth string) ([]string, map[string][]Report, error) {
+	if err := c.EnsureLogin(); err != nil {
+		return nil, nil, err
 	}
 
-	end, err := time.Parse("2006-01", endMonth)
+	// 生成月份范围
+	months, err := c.GenerateMonthRange(startMonth, endMonth)
 	if err != nil {
-		return nil, fmt.Errorf("结束月份格式错误: %w", err)
+		return nil, nil, fmt.Errorf("生成月份范围失败: %w", err)
 	}
 
-	var months []string
-	current := start
-	for !current.After(end) {
-		months = append(months, current.Format("2006-01"))
-		current = current.AddDate(0, 1, 0)
+	// 采集所有周期的数据
+	rt := c.reportType
+	keys := rt.QueryKeys(months)
+	allReports := make(map[string][]Report)
+	var fetchErr *FetchError
+	for i, key := range keys {
+		if err := c.ctx.Err(); err != nil {
+			return nil, nil, fmt.Errorf("采集已取消: %w", err)
+		}
+
+		if reports, ok := c.checkpoint.lookup(key); ok {
+			allReports[key] = reports
+			log.Printf("  ✓ %s 已在检查点中（%d 条%s），跳过", key, len(reports), rt.Label)
+			if c.progress != nil {
+				c.progress(i+1, len(keys), key, reports)
+			}
+			continue
+		}
+
+		log.Printf("正在采集 %s %s...", key, rt.Label)
+		reports, err := c.FetchReports(key)
+		if err != nil {
+			log.Printf("采集 %s 失败: %v", key, err)
+			if c.checkpoint != nil {
+				c.checkpoint.fail(key, err)
+				continue
+			}
+			if fetchErr == nil {
+				fetchErr = &FetchError{Errs: make(map[string]error)}
+			}
+			fetchErr.Failed = append(fetchErr.Failed, key)
+			fetchErr.Errs[key] = err
+			continue
+		}
+
+		// 按年查询时，去掉不在月份范围内的报告
+		if rt.QueryLayout != "2006-01" {
+			reports = filterMonths(reports, months)
+		}
+
+		allReports[key] = reports
+		log.Printf("  ✓ 采集到 %d 条%s", len(reports), rt.Label)
+		if c.checkpoint != nil {
+			c.checkpoint.complete(key, reports)
+		}
+		if c.progress != nil {
+			c.progress(i+1, len(keys), key, reports)
+		}
 	}
 
-	return months, nil
+	if fetchErr != nil {
+		return keys, allReports, fetchErr
+	}
+	return keys, allReports, nil
 }
 
-// Collect 采集指定月份范围的日报并保存
-func (c *Collector) Collect(startMonth, endMonth, outputFile string) (string, error) {
+// CollectRange 采集覆盖日期范围的各月份报告，再按日期精确过滤（日期未识别的保留）。
+// 部分周期失败时同 CollectReports，返回已采集部分和 *FetchError
+func (c *Collector) CollectRange(r daterange.Range) ([]string, map[string][]Report, error) {
+	months := r.Months()
+	keys, allReports, err := c.CollectReports(months[0], months[len(months)-1])
+	var fetchErr *FetchError
+	if err != nil && !errors.As(err, &fetchErr) {
+		return nil, nil, err
+	}
+
+	for key, reports := range allReports {
+		allReports[key] = filterRange(reports, r)
+	}
+	return keys, allReports, err
+}
+
+// Collect 采集指定日期范围的报告并保存
+func (c *Collector) Collect(r daterange.Range, outputFile string) (string, error) {
 	// 处理输出文件路径
 	if outputFile == "" {
 		outputFile = c.getDefaultOutputFile()
@@ -161,44 +364,45 @@ func (c *Collector) Collect(startMonth, endMonth, outputFile string) (string, er
 		outputFile = filepath.Join(c.getDefaultOutputDir(), outputFile)
 	}
 
-	// 确保输出目录存在
-	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
-		return "", fmt.Errorf("创建输出目录失败: %w", err)
+	// on_conflict=fail 时先检查，避免采集完才发现无法写入
+	if err := fileutil.CheckConflict(outputFile, c.output); err != nil {
+		return "", err
 	}
 
-	// 加载已保存的 Cookie
-	if c.cookieManager.HasCookies() {
-		if err := c.LoadSavedCookies(); err != nil {
-			return "", fmt.Errorf("加载 Cookie 失败: %w", err)
-		}
+	cp, err := c.openCheckpoint(r)
+	if err != nil {
+		return "", err
 	}
+	c.checkpoint = cp
+	defer func() { c.checkpoint = nil }()
 
-	// 检查登录状态
-	if !c.CheckLoginStatus() {
-		return "", fmt.Errorf("未登录或登录已过期，请先使用 browser_login 工具登录")
+	months, allReports, err := c.CollectRange(r)
+	if err != nil {
+		return "", fmt.Errorf("%w（已完成的周期已保存到检查点，可使用 resume 继续）", err)
 	}
 
-	// 生成月份范围
-	months, err := c.GenerateMonthRange(startMonth, endMonth)
-	if err != nil {
-		return "", fmt.Errorf("生成月份范围失败: %w", err)
+	// 有周期失败时默认不导出，避免生成不完整的文件
+	pending := cp.Pending(months)
+	if len(pending) > 0 && !c.allowPartial {
+		return "", fmt.Errorf("%d 个周期采集失败（%s），已完成的周期已保存到检查点，可使用 resume 继续，或使用 partial 导出已完成部分",
+			len(pending), strings.Join(pending, "、"))
 	}
 
-	// 采集所有月份的数据
-	allReports := make(map[string][]Report)
+	// 附件目录跟随请求的文件名，on_conflict=version 另存时新文件也引用同一目录
+	var assetsNote string
+	if c.downloadAssets {
+		dir := AssetsDir(outputFile)
+		assetsNote = fmt.Sprintf("，新保存附件 %d 个到 %s", c.localizeAssets(allReports, dir), dir)
+	}
+
+	c.collected = nil
 	for _, month := range months {
-		log.Printf("正在采集 %s 月份日报...", month)
-		reports, err := c.FetchMonthReports(month)
-		if err != nil {
-			log.Printf("采集 %s 月份失败: %v", month, err)
-			continue
-		}
-		allReports[month] = reports
-		log.Printf("  ✓ 采集到 %d 条日报", len(reports))
+		c.collected = append(c.collected, allReports[month]...)
 	}
 
 	// 生成 Markdown 文件
-	if err := c.generateMarkdown(allReports, outputFile); err != nil {
+	outputFile, err = c.generateMarkdown(allReports, pending, outputFile)
+	if err != nil {
 		return "", fmt.Errorf("生成 Markdown 失败: %w", err)
 	}
 
@@ -207,22 +411,38 @@ func (c *Collector) Collect(startMonth, endMonth, outputFile string) (string, er
 		totalCount += len(reports)
 	}
 
-	return fmt.Sprintf("✓ 采集完成！共采集 %d 个月份，%d 条日报，已保存到 %s",
-		len(months), totalCount, outputFile), nil
+	if len(pending) > 0 {
+		return fmt.Sprintf("⚠️ 部分导出！范围 %s，%d 个周期未完成（%s），已导出 %d 条%s到 %s%s，可使用 resume 补齐",
+			r, len(pending), strings.Join(pending, "、"), totalCount, c.reportType.Label, outputFile, assetsNote), nil
+	}
+	cp.remove()
+
+	return fmt.Sprintf("✓ 采集完成！范围 %s，共采集 %d 个周期，%d 条%s，已保存到 %s%s",
+		r, len(months), totalCount, c.reportType.Label, outputFile, assetsNote), nil
 }
 
-// generateMarkdown 生成 Markdown 文件
-func (c *Collector) generateMarkdown(allReports map[string][]Report, outputFile string) error {
-	f, err := os.Create(outputFile)
+// Collected 返回最近一次 Collect 采集到的报告
+func (c *Collector) Collected() []Report {
+	return c.collected
+}
+
+// generateMarkdown 生成 Markdown 文件并返回实际写入的路径，pending 为未完成采集的周期（部分导出时在开头标注）
+func (c *Collector) generateMarkdown(allReports map[string][]Report, pending []string, outputFile string) (string, error) {
+	f, err := fileutil.Create(outputFile, c.output)
 	if err != nil {
-		return fmt.Errorf("创建文件失败: %w", err)
+		return "", err
 	}
-	defer f.Close()
+	defer f.Abort()
+
+	rt := c.reportType
 
 	// 写入标题
-	fmt.Fprintln(f, "# YST 日报整理")
+	fmt.Fprintf(f, "# YST %s整理\n", rt.Label)
 	fmt.Fprintln(f)
 	fmt.Fprintf(f, "生成时间：%s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+	if len(pending) > 0 {
+		fmt.Fprintf(f, "> ⚠️ 部分导出：%s 未完成采集\n\n", strings.Join(pending, "、"))
+	}
 
 	// 按月份排序输出
 	var months []string
@@ -238,58 +458,176 @@ func (c *Collector) generateMarkdown(allReports map[string][]Report, outputFile
 		}
 	}
 
+	// 周报、月报按报告自身的周期分组输出
+	if rt.Name != TypeDaily {
+		var all []Report
+		for _, month := range months {
+			all = append(all, allReports[month]...)
+		}
+		periods, groups := rt.GroupByPeriod(all)
+		for _, period := range periods {
+			writeReportSection(f, rt.PeriodTitle(period), groups[period])
+		}
+		if len(periods) == 0 {
+			fmt.Fprint(f, "*暂无数据*\n\n")
+		}
+		return f.Commit()
+	}
+
 	for _, month := range months {
 		reports := allReports[month]
-		fmt.Fprintf(f, "## %s 月份日报 (%d 条)\n\n", month, len(reports))
+		fmt.Fprintf(f, "## %s 月份%s (%d 条)\n\n", month, rt.Label, len(reports))
 
 		if len(reports) == 0 {
-			fmt.Fprintln(f, "*暂无数据*\n")
+			fmt.Fprint(f, "*暂无数据*\n\n")
 			continue
 		}
 
 		for i, report := range reports {
-			fmt.Fprintf(f, "### %d. %s\n\n", i+1, report.Text)
-			if report.Link != "" {
-				fmt.Fprintf(f, "链接：%s\n\n", report.Link)
-			}
-			fmt.Fprintln(f, "---\n")
+			writeReport(f, i, report)
 		}
 	}
 
-	return nil
+	return f.Commit()
 }
 
-// getDefaultOutputDir 获取默认输出目录
-func (c *Collector) getDefaultOutputDir() string {
-	// 优先使用当前工作目录（AI 客户端的项目目录）
-	if cwd, err := os.Getwd(); err == nil {
-		// 检查当前目录是否可写
-		testFile := filepath.Join(cwd, ".yst_test_write")
-		if f, err := os.Create(testFile); err == nil {
-			f.Close()
-			os.Remove(testFile)
-			log.Printf("使用当前工作目录作为输出目录: %s", cwd)
-			return cwd
-		}
+// writeReportSection 输出一个周期的报告
+func writeReportSection(w io.Writer, title string, reports []Report) {
+	fmt.Fprintf(w, "## %s (%d 条)\n\n", title, len(reports))
+	for i, report := range reports {
+		writeReport(w, i, report)
 	}
+}
 
-	// 如果当前目录不可写，使用用户主目录
-	homeDir, err := os.UserHomeDir()
-	if err == nil {
-		outputDir := filepath.Join(homeDir, ".yst_go_mcp", "output")
-		os.MkdirAll(outputDir, 0755)
-		log.Printf("使用用户主目录作为输出目录: %s", outputDir)
-		return outputDir
+// writeReport 输出一份报告：标题、Markdown 正文、链接和附件，正文中的附件地址替换为本地副本
+func writeReport(w io.Writer, i int, report Report) {
+	title, body := splitReport(report)
+	fmt.Fprintf(w, "### %d. %s\n\n", i+1, title)
+	if body != "" {
+		fmt.Fprintf(w, "%s\n\n", rewriteAssetLinks(body, report.Attachments))
+	}
+	if report.Link != "" {
+		fmt.Fprintf(w, "链接：%s\n\n", report.Link)
+	}
+	writeAttachments(w, report.Attachments)
+	fmt.Fprint(w, "---\n\n")
+}
+
+// markdownLinkRe 比较标题时把 Markdown 链接和图片还原为文字
+var markdownLinkRe = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+
+// splitReport 拆分报告标题（纯文本第一行）和正文；正文优先使用 Markdown，开头与标题相同的一行不再重复
+func splitReport(r Report) (string, string) {
+	title, rest, _ := strings.Cut(strings.TrimSpace(r.Text), "\n")
+	title = strings.TrimSpace(title)
+	if r.Markdown == "" {
+		return title, strings.TrimSpace(rest)
 	}
 
-	// 最后的备选方案
-	log.Println("使用相对路径 output 作为输出目录")
-	return "output"
+	first, mdRest, _ := strings.Cut(strings.TrimSpace(r.Markdown), "\n")
+	if looseText(first) == looseText(title) {
+		return title, strings.TrimSpace(mdRest)
+	}
+	return title, strings.TrimSpace(r.Markdown)
+}
+
+// looseText 去掉 Markdown 标记和空白，用于比较一行文字是否相同
+func looseText(s string) string {
+	s = markdownLinkRe.ReplaceAllString(s, "$1")
+	return strings.NewReplacer("\\", "", "*", "", "`", "", " ", "", "\t", "").Replace(s)
+}
+
+// getDefaultOutputDir 获取默认输出目录（macOS/Linux 为下载目录，Windows 为桌面）
+func (c *Collector) getDefaultOutputDir() string {
+	return paths.Output()
 }
 
 // getDefaultOutputFile 获取默认输出文件
 func (c *Collector) getDefaultOutputFile() string {
-	return filepath.Join(c.getDefaultOutputDir(), "日报详情.md")
+	return filepath.Join(c.getDefaultOutputDir(), c.reportType.Label+"详情.md")
+}
+
+// ResolveLink 把列表页中的相对链接转换为完整地址
+func ResolveLink(link string) (string, error) {
+	base, _ := url.Parse(BaseURL)
+	u, err := base.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return "", fmt.Errorf("日报链接无效 %q: %w", link, err)
+	}
+	return u.String(), nil
+}
+
+// ParseMarkdownReports 从导出的日报 Markdown 中还原日报列表：
+// 标题行之后、链接行之前的内容为 Markdown 正文，Text 为标题加正文的纯文本；"附件：" 之后的列表为附件
+func ParseMarkdownReports(content string) []Report {
+	const (
+		outside = iota
+		inBody
+		inAttachments
+	)
+	var (
+		reports []Report
+		body    []string
+		state   = outside
+		fence   string // 正文中未闭合的代码块围栏，代码块内的行原样保留
+	)
+	endBody := func() {
+		if md := strings.Trim(strings.Join(body, "\n"), "\n"); md != "" && len(reports) > 0 {
+			last := &reports[len(reports)-1]
+			last.Markdown = md
+			last.Text += "\n" + htmlmd.Plain(md)
+			// 与采集时一样从全文识别日期，日期不一定在第一行
+			last.Date, last.SubmittedAt = ParseReportDates(last.Text)
+		}
+		body = nil
+	}
+
+	for _, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(raw)
+		if state == inBody && fence != "" {
+			if line == fence {
+				fence = ""
+			}
+			body = append(body, strings.TrimRight(raw, "\r"))
+			continue
+		}
+		switch {
+		case state == inBody && strings.HasPrefix(line, "```"):
+			fence = line[:len(line)-len(strings.TrimLeft(line, "`"))]
+			body = append(body, strings.TrimRight(raw, "\r"))
+		case strings.HasPrefix(line, "### "):
+			endBody()
+			text := strings.TrimPrefix(line, "### ")
+			// 去掉 "1. " 序号
+			if idx := strings.Index(text, ". "); idx > 0 {
+				if _, err := strconv.Atoi(text[:idx]); err == nil {
+					text = text[idx+2:]
+				}
+			}
+			date, submittedAt := ParseReportDates(text)
+			reports = append(reports, Report{Text: text, Date: date, SubmittedAt: submittedAt})
+			state = inBody
+		case strings.HasPrefix(line, "## "), line == "---":
+			endBody()
+			state = outside
+		case state == outside:
+		case strings.HasPrefix(line, "链接："):
+			endBody()
+			reports[len(reports)-1].Link = strings.TrimPrefix(line, "链接：")
+		case line == "附件：":
+			endBody()
+			state = inAttachments
+		case state == inAttachments:
+			if a, ok := parseAttachmentLine(line); ok {
+				last := &reports[len(reports)-1]
+				last.Attachments = append(last.Attachments, a)
+			}
+		case state == inBody && (len(body) > 0 || line != ""):
+			body = append(body, strings.TrimRight(raw, "\r"))
+		}
+	}
+	endBody()
+	return reports
 }
 
 // ReadMarkdownForSummary 读取日报 MD 文件，返回内容和 CSV 保存路径
@@ -0,0 +1,132 @@
+package collector
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/Xuzan9396/yst_go_mcp/internal/htmlmd"
+)
+
+// reportFromHTML 按采集时的方式从列表项 HTML 构造报告
+func reportFromHTML(t *testing.T, html string) Report {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader("<ul>" + html + "</ul>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	li := doc.Find("li").First()
+	text := htmlmd.Text(li)
+	date, submittedAt := ParseReportDates(text)
+	return Report{
+		Text:        text,
+		Markdown:    htmlmd.Markdown(li, nil),
+		Link:        "/report/report-daily/view?id=1",
+		Date:        date,
+		SubmittedAt: submittedAt,
+	}
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		date     string
+		contains []string // 读回的 Markdown 正文中应包含的内容
+	}{
+		{
+			name:     "列表和链接",
+			html:     `<li><a href="/view?id=1">2025-03-10 日报</a><p>今日完成：</p><ol><li>修复 <code>login</code></li><li>评审 <a href="https://git.example.com/pr/1">PR</a></li></ol></li>`,
+			date:     "2025-03-10",
+			contains: []string{"1. 修复 `login`", "2. 评审 [PR](https://git.example.com/pr/1)"},
+		},
+		{
+			name:     "表格",
+			html:     `<li><p>2025-03-11 日报</p><table><tr><th>事项</th><th>工时</th></tr><tr><td>联调</td><td>3</td></tr></table></li>`,
+			date:     "2025-03-11",
+			contains: []string{"| 事项 | 工时 |", "| --- | --- |", "| 联调 | 3 |"},
+		},
+		{
+			name:     "代码块中的分隔线和标题",
+			html:     "<li><p>2025-03-12 日报</p><pre>---\n## 不是标题\n链接：不是链接\n### 1. 也不是报告</pre><p>结束</p></li>",
+			date:     "2025-03-12",
+			contains: []string{"```\n---\n## 不是标题\n链接：不是链接\n### 1. 也不是报告\n```", "结束"},
+		},
+		{
+			name:     "日期不在第一行",
+			html:     `<li><p>完成登录模块</p><span>日期 2025-03-13 提交于 2025-03-14 09:30</span></li>`,
+			date:     "2025-03-13",
+			contains: []string{"2025-03-13"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := reportFromHTML(t, tt.html)
+			if got := original.Date.Format("2006-01-02"); got != tt.date {
+				t.Fatalf("采集时日期 = %s，期望 %s", got, tt.date)
+			}
+
+			var first bytes.Buffer
+			writeReport(&first, 0, original)
+			reports := ParseMarkdownReports("## 2025-03\n\n" + first.String())
+			if len(reports) != 1 {
+				t.Fatalf("读回 %d 份报告，期望 1 份：\n%s", len(reports), first.String())
+			}
+			got := reports[0]
+
+			if !got.Date.Equal(original.Date) {
+				t.Errorf("读回日期 = %v，期望 %v", got.Date, original.Date)
+			}
+			if !got.SubmittedAt.Equal(original.SubmittedAt) {
+				t.Errorf("读回提交时间 = %v，期望 %v", got.SubmittedAt, original.SubmittedAt)
+			}
+			if got.Link != original.Link {
+				t.Errorf("读回链接 = %q，期望 %q", got.Link, original.Link)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got.Markdown, want) {
+					t.Errorf("读回正文缺少 %q：\n%s", want, got.Markdown)
+				}
+			}
+
+			// 读回后再次写出应与第一次完全相同
+			var second bytes.Buffer
+			writeReport(&second, 0, got)
+			if second.String() != first.String() {
+				t.Errorf("再次写出不一致：\n第一次：\n%s\n第二次：\n%s", first.String(), second.String())
+			}
+		})
+	}
+}
+
+func TestParseMarkdownReportsLegacy(t *testing.T) {
+	// 旧版本导出的文件：正文都在标题行中
+	content := "# YST 日报整理\n\n## 2025-03\n\n### 1. 2025-03-10 日报 修复登录\n\n链接：/view?id=1\n\n---\n\n" +
+		"### 2. 2025-03-11 日报 联调\n\n链接：/view?id=2\n\n附件：\n\n- ![截图](日报_assets/a.png)\n\n---\n"
+	reports := ParseMarkdownReports(content)
+	if len(reports) != 2 {
+		t.Fatalf("读回 %d 份报告，期望 2 份", len(reports))
+	}
+
+	want := []struct {
+		text, link string
+		date       time.Time
+		attach     int
+	}{
+		{"2025-03-10 日报 修复登录", "/view?id=1", time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local), 0},
+		{"2025-03-11 日报 联调", "/view?id=2", time.Date(2025, 3, 11, 0, 0, 0, 0, time.Local), 1},
+	}
+	for i, w := range want {
+		r := reports[i]
+		if r.Text != w.text || r.Link != w.link || !r.Date.Equal(w.date) || len(r.Attachments) != w.attach {
+			t.Errorf("第 %d 份 = {%q %q %v 附件 %d}，期望 {%q %q %v 附件 %d}",
+				i+1, r.Text, r.Link, r.Date, len(r.Attachments), w.text, w.link, w.date, w.attach)
+		}
+	}
+	if reports[1].Attachments[0].Local != "日报_assets/a.png" || !reports[1].Attachments[0].Image {
+		t.Errorf("附件 = %+v", reports[1].Attachments[0])
+	}
+}
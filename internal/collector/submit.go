@@ -0,0 +1,394 @@
+package collector
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ReportCreateURL 新建日报表单页面
+const ReportCreateURL = BaseURL + "/report/report-daily/create"
+
+// csrfParam 站点（Yii2）默认的 CSRF 参数名
+const csrfParam = "_csrf"
+
+// SubmitRequest 提交或修改日报的参数
+type SubmitRequest struct {
+	Date         time.Time
+	Content      string
+	ReportURL    string // 修改时可直接指定日报链接，为空则按日期查找
+	ContentField string // 内容字段名，为空则自动识别
+	DateField    string // 日期字段名，为空则自动识别
+	DryRun       bool
+}
+
+// SubmitResult 提交或修改日报的结果
+type SubmitResult struct {
+	Action   string
+	FormURL  string
+	PostURL  string
+	Fields   url.Values
+	Verified bool
+	Report   *Report
+}
+
+// reportForm 从页面中解析出的日报表单
+type reportForm struct {
+	action       string
+	fields       url.Values
+	contentField string
+	dateField    string
+}
+
+// SubmitDailyReport 新建指定日期的日报
+func (c *Collector) SubmitDailyReport(req SubmitRequest) (*SubmitResult, error) {
+	if err := c.EnsureLogin(); err != nil {
+		return nil, err
+	}
+
+	existing, err := c.FindReportByDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%s 已有日报（%s），请使用 update_daily_report 修改", req.Date.Format("2006-01-02"), existing.Link)
+	}
+
+	return c.submitForm("submit", ReportCreateURL, req)
+}
+
+// UpdateDailyReport 修改指定日期（或指定链接）的日报
+func (c *Collector) UpdateDailyReport(req SubmitRequest) (*SubmitResult, error) {
+	if err := c.EnsureLogin(); err != nil {
+		return nil, err
+	}
+
+	link := req.ReportURL
+	if link == "" {
+		existing, err := c.FindReportByDate(req.Date)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, fmt.Errorf("%s 没有找到日报，请使用 submit_daily_report 新建", req.Date.Format("2006-01-02"))
+		}
+		link = existing.Link
+	}
+
+	formURL, err := updateURL(link)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.submitForm("update", formURL, req)
+}
+
+// submitForm 获取表单、填写内容并提交，最后重新读取列表验证结果
+func (c *Collector) submitForm(action, formURL string, req SubmitRequest) (*SubmitResult, error) {
+	log.Printf("正在获取日报表单: %s", formURL)
+	doc, err := c.fetchDocument(formURL)
+	if err != nil {
+		return nil, fmt.Errorf("获取日报表单失败: %w", err)
+	}
+
+	form, err := parseReportForm(doc, req.ContentField, req.DateField)
+	if err != nil {
+		return nil, err
+	}
+
+	form.fields.Set(form.contentField, req.Content)
+	if form.dateField != "" {
+		form.fields.Set(form.dateField, req.Date.Format("2006-01-02"))
+	}
+
+	result := &SubmitResult{
+		Action:  action,
+		FormURL: formURL,
+		PostURL: form.action,
+		Fields:  form.fields,
+	}
+	if req.DryRun {
+		return result, nil
+	}
+
+	log.Printf("正在提交日报: %s", form.action)
+	if err := c.postForm(form.action, formURL, form.fields); err != nil {
+		return nil, err
+	}
+
+	report, verified, err := c.verifySubmission(req.Date, req.Content)
+	if err != nil {
+		return nil, fmt.Errorf("提交成功但验证失败: %w", err)
+	}
+	result.Report = report
+	result.Verified = verified
+
+	return result, nil
+}
+
+// verifyPrefixRunes 验证提交结果时比较的内容开头长度（字符数）
+const verifyPrefixRunes = 30
+
+// verifySubmission 重新读取列表，确认该日期的报告内容是刚提交的内容；列表中的正文可能被截断，不匹配时再读取详情页
+func (c *Collector) verifySubmission(date time.Time, content string) (*Report, bool, error) {
+	report, err := c.FindReportByDate(date)
+	if err != nil || report == nil {
+		return report, false, err
+	}
+	if contentMatches(report.Text, content) {
+		return report, true, nil
+	}
+	if report.Link == "" {
+		return report, false, nil
+	}
+
+	link, err := ResolveLink(report.Link)
+	if err != nil {
+		return report, false, err
+	}
+	doc, err := c.fetchDocument(link)
+	if err != nil {
+		return report, false, fmt.Errorf("读取%s详情失败: %w", c.reportType.Label, err)
+	}
+	return report, contentMatches(doc.Find("body").Text(), content), nil
+}
+
+// contentMatches 判断页面文本中是否包含提交内容的开头，忽略空白差异
+func contentMatches(pageText, content string) bool {
+	want := []rune(compactText(content))
+	if len(want) > verifyPrefixRunes {
+		want = want[:verifyPrefixRunes]
+	}
+	return len(want) > 0 && strings.Contains(compactText(pageText), string(want))
+}
+
+// compactText 去掉所有空白
+func compactText(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
+
+// postForm 以表单方式提交，并检查返回页面中的校验错误
+func (c *Collector) postForm(postURL, referer string, fields url.Values) error {
+	httpReq, err := http.NewRequestWithContext(c.ctx, "POST", postURL, strings.NewReader(fields.Encode()))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+
+	setBrowserHeaders(httpReq)
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("Referer", referer)
+	httpReq.Header.Set("Origin", BaseURL)
+	for name := range fields {
+		if strings.Contains(strings.ToLower(name), "csrf") {
+			httpReq.Header.Set("X-CSRF-Token", fields.Get(name))
+		}
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("提交失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("提交失败，HTTP 状态码: %d", resp.StatusCode)
+	}
+	if strings.Contains(resp.Request.URL.String(), "login") {
+		return fmt.Errorf("提交失败：登录已过期，请重新登录")
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return fmt.Errorf("解析提交结果失败: %w", err)
+	}
+
+	// 校验失败时站点会重新渲染表单并显示错误信息
+	var messages []string
+	doc.Find(".error-summary li, .help-block-error, .invalid-feedback, .alert-danger").Each(func(i int, s *goquery.Selection) {
+		if text := strings.TrimSpace(s.Text()); text != "" {
+			messages = append(messages, text)
+		}
+	})
+	if len(messages) > 0 {
+		return fmt.Errorf("站点拒绝了提交: %s", strings.Join(messages, "；"))
+	}
+
+	return nil
+}
+
+// FindReportByDate 读取报告列表，查找指定日期所在周期的报告（日报为当天，周报为当周，月报为当月）
+func (c *Collector) FindReportByDate(date time.Time) (*Report, error) {
+	reports, err := c.FetchReports(date.Format(c.reportType.QueryLayout))
+	if err != nil {
+		return nil, fmt.Errorf("读取%s列表失败: %w", c.reportType.Label, err)
+	}
+
+	period := c.reportType.PeriodOf(date)
+	for _, r := range reports {
+		if r.Period != "" && r.Period == period {
+			r := r
+			return &r, nil
+		}
+	}
+	return nil, nil
+}
+
+// parseReportForm 解析页面中的日报表单，保留隐藏字段（含 CSRF Token）
+func parseReportForm(doc *goquery.Document, contentField, dateField string) (*reportForm, error) {
+	formSel := doc.Find("form").FilterFunction(func(i int, s *goquery.Selection) bool {
+		return s.Find("textarea").Length() > 0
+	}).First()
+	if formSel.Length() == 0 {
+		return nil, fmt.Errorf("页面中没有找到日报表单（可能未登录或页面结构已变化）")
+	}
+
+	form := &reportForm{fields: url.Values{}}
+
+	action, _ := formSel.Attr("action")
+	actionURL, err := doc.Url.Parse(action)
+	if err != nil {
+		return nil, fmt.Errorf("表单地址无效: %w", err)
+	}
+	form.action = actionURL.String()
+
+	var textareas, dateInputs, namedDates []string
+	formSel.Find("input, textarea, select").Each(func(i int, s *goquery.Selection) {
+		name, ok := s.Attr("name")
+		if !ok || name == "" {
+			return
+		}
+		if _, disabled := s.Attr("disabled"); disabled {
+			return
+		}
+
+		switch goquery.NodeName(s) {
+		case "textarea":
+			textareas = append(textareas, name)
+			form.fields.Set(name, s.Text())
+		case "select":
+			value, _ := s.Find("option[selected]").Attr("value")
+			form.fields.Set(name, value)
+		default:
+			inputType := strings.ToLower(s.AttrOr("type", "text"))
+			switch inputType {
+			case "submit", "button", "file", "image", "reset":
+				return
+			case "checkbox", "radio":
+				if _, checked := s.Attr("checked"); !checked {
+					return
+				}
+			}
+			form.fields.Add(name, s.AttrOr("value", ""))
+			// type="date" 的输入框优先，其次名称中有 date、day 单词的字段（不匹配 updated_at、today）
+			if inputType == "date" {
+				dateInputs = append(dateInputs, name)
+			} else if hasToken(name, "date") || hasToken(name, "day") {
+				namedDates = append(namedDates, name)
+			}
+		}
+	})
+
+	// 页面 meta 中的 CSRF Token 优先于表单隐藏字段
+	if token, ok := doc.Find(`meta[name="csrf-token"]`).Attr("content"); ok {
+		param := doc.Find(`meta[name="csrf-param"]`).AttrOr("content", csrfParam)
+		form.fields.Set(param, token)
+	}
+
+	form.contentField = pickField(contentField, textareas, "content")
+	if form.contentField == "" {
+		return nil, fmt.Errorf("没有找到日报内容字段，可用字段: %s", strings.Join(textareas, ", "))
+	}
+	form.dateField = pickField(dateField, append(dateInputs, namedDates...), "date")
+
+	return form, nil
+}
+
+// pickField 选择字段：优先使用指定值，其次名称中有关键字单词的字段，最后取第一个
+func pickField(explicit string, candidates []string, keyword string) string {
+	if explicit != "" {
+		return explicit
+	}
+	for _, name := range candidates {
+		if hasToken(name, keyword) {
+			return name
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0]
+	}
+	return ""
+}
+
+// hasToken 判断字段名按分隔符和驼峰拆分后是否含有某个单词，
+// 例如 ReportDaily[report_date]、reportDate 含有 date，updated_at、today 不含 date、day
+func hasToken(name, token string) bool {
+	var words []string
+	var word []rune
+	prev := rune(0)
+	for _, r := range name {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			words = append(words, string(word))
+			word = word[:0]
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			words = append(words, string(word))
+			word = append(word[:0], r)
+		default:
+			word = append(word, r)
+		}
+		prev = r
+	}
+	words = append(words, string(word))
+
+	for _, w := range words {
+		if strings.EqualFold(w, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateURL 将日报查看链接转换为编辑链接，例如 /view?id=1 -> /update?id=1
+func updateURL(link string) (string, error) {
+	abs, err := ResolveLink(link)
+	if err != nil {
+		return "", err
+	}
+	u, _ := url.Parse(abs)
+	if strings.Contains(u.Path, "/update") {
+		return u.String(), nil
+	}
+	if !strings.Contains(u.Path, "/view") {
+		return "", fmt.Errorf("无法从链接 %q 推断编辑地址，请通过 report_url 指定编辑页面", link)
+	}
+	u.Path = strings.Replace(u.Path, "/view", "/update", 1)
+	return u.String(), nil
+}
+
+// Preview 生成提交内容预览，隐藏 CSRF Token
+func (r *SubmitResult) Preview() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "表单页面：%s\n提交地址：%s\n\n", r.FormURL, r.PostURL)
+
+	var names []string
+	for name := range r.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := strings.Join(r.Fields[name], ", ")
+		if strings.Contains(strings.ToLower(name), "csrf") {
+			value = "******"
+		}
+		fmt.Fprintf(&b, "- %s = %s\n", name, value)
+	}
+	return b.String()
+}
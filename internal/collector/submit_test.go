@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestHasToken(t *testing.T) {
+	tests := []struct {
+		name, token string
+		want        bool
+	}{
+		{"date", "date", true},
+		{"report_date", "date", true},
+		{"ReportDaily[date]", "date", true},
+		{"ReportDaily[report_date]", "date", true},
+		{"reportDate", "date", true},
+		{"REPORT_DATE", "date", true},
+		{"work-day", "day", true},
+		{"updated_at", "date", false},
+		{"update", "date", false},
+		{"today", "day", false},
+		{"ReportDaily[daily]", "day", false},
+		{"holidays", "day", false},
+	}
+	for _, tt := range tests {
+		if got := hasToken(tt.name, tt.token); got != tt.want {
+			t.Errorf("hasToken(%q, %q) = %v，期望 %v", tt.name, tt.token, got, tt.want)
+		}
+	}
+}
+
+func TestParseReportFormDateField(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs string
+		want   string
+	}{
+		{
+			name:   "不把 updated_at、today 当作日期字段",
+			inputs: `<input name="updated_at" value="1"><input name="today" value="x"><input name="ReportDaily[report_date]">`,
+			want:   "ReportDaily[report_date]",
+		},
+		{
+			name:   "type=date 优先",
+			inputs: `<input name="ReportDaily[day]"><input type="date" name="ReportDaily[when]">`,
+			want:   "ReportDaily[when]",
+		},
+		{
+			name:   "名称含 day 单词",
+			inputs: `<input name="ReportDaily[day]">`,
+			want:   "ReportDaily[day]",
+		},
+		{
+			name:   "没有日期字段",
+			inputs: `<input name="updated_at"><input type="hidden" name="_csrf" value="t">`,
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html := `<form action="/report/report-daily/create">` + tt.inputs + `<textarea name="ReportDaily[content]"></textarea></form>`
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc.Url, _ = url.Parse(ReportCreateURL)
+
+			form, err := parseReportForm(doc, "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if form.dateField != tt.want {
+				t.Errorf("dateField = %q，期望 %q", form.dateField, tt.want)
+			}
+			if form.contentField != "ReportDaily[content]" {
+				t.Errorf("contentField = %q", form.contentField)
+			}
+		})
+	}
+}
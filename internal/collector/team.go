@@ -0,0 +1,233 @@
+package collector
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/Xuzan9396/yst_go_mcp/internal/daterange"
+	"github.com/Xuzan9396/yst_go_mcp/internal/fileutil"
+)
+
+// memberParam 团队列表页按成员筛选的查询参数
+const memberParam = "user_id"
+
+// Member 团队成员
+type Member struct {
+	ID   string
+	Name string
+}
+
+// MemberReports 单个成员在各周期的报告
+type MemberReports struct {
+	Member  Member
+	Reports map[string][]Report
+	Failed  []string // 采集失败的查询周期，这些周期没有数据，不能当作缺失
+}
+
+// All 返回成员的全部报告（按查询周期顺序）
+func (m *MemberReports) All(keys []string) []Report {
+	var all []Report
+	for _, key := range keys {
+		all = append(all, m.Reports[key]...)
+	}
+	return all
+}
+
+// ListTeamMembers 从团队报告列表页的成员筛选框中读取下属成员
+func (c *Collector) ListTeamMembers() ([]Member, error) {
+	doc, err := c.fetchDocument(c.reportType.TeamListURL)
+	if err != nil {
+		return nil, fmt.Errorf("读取团队列表失败: %w", err)
+	}
+
+	var members []Member
+	seen := make(map[string]bool)
+	add := func(id, name string) {
+		id, name = strings.TrimSpace(id), strings.TrimSpace(name)
+		if id == "" || seen[id] {
+			return
+		}
+		seen[id] = true
+		members = append(members, Member{ID: id, Name: name})
+	}
+
+	doc.Find(fmt.Sprintf(`select[name="%s"] option, select[name*="user"] option`, memberParam)).Each(func(i int, s *goquery.Selection) {
+		add(s.AttrOr("value", ""), s.Text())
+	})
+
+	// 没有筛选框时，从带 user_id 参数的链接中识别成员
+	if len(members) == 0 {
+		doc.Find(fmt.Sprintf(`a[href*="%s="]`, memberParam)).Each(func(i int, s *goquery.Selection) {
+			href, _ := s.Attr("href")
+			if u, err := url.Parse(href); err == nil {
+				add(u.Query().Get(memberParam), s.Text())
+			}
+		})
+	}
+
+	if len(members) == 0 {
+		return nil, fmt.Errorf("没有找到团队成员（当前账号可能不是团队负责人）")
+	}
+
+	return members, nil
+}
+
+// FilterMembers 按 ID 或姓名筛选成员，names 为空时返回全部
+func FilterMembers(members []Member, names []string) ([]Member, error) {
+	if len(names) == 0 {
+		return members, nil
+	}
+
+	var result []Member
+	for _, name := range names {
+		found := false
+		for _, m := range members {
+			if m.ID == name || m.Name == name {
+				result = append(result, m)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("团队中没有成员 %q", name)
+		}
+	}
+	return result, nil
+}
+
+// FetchMemberReports 获取指定成员在某个周期的报告
+func (c *Collector) FetchMemberReports(memberID, query string) ([]Report, error) {
+	rt := c.reportType
+	pageURL := fmt.Sprintf("%s?%s=%s&%s=%s", rt.TeamListURL,
+		memberParam, url.QueryEscape(memberID), rt.QueryParam, url.QueryEscape(query))
+
+	doc, err := c.fetchDocument(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	return rt.parseList(doc), nil
+}
+
+// CollectTeamReports 采集团队成员在指定日期范围的报告
+func (c *Collector) CollectTeamReports(r daterange.Range, names []string) ([]string, []MemberReports, error) {
+	if err := c.EnsureLogin(); err != nil {
+		return nil, nil, err
+	}
+
+	months := r.Months()
+
+	members, err := c.ListTeamMembers()
+	if err != nil {
+		return nil, nil, err
+	}
+	members, err = FilterMembers(members, names)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rt := c.reportType
+	keys := rt.QueryKeys(months)
+	var team []MemberReports
+	for _, member := range members {
+		mr := MemberReports{Member: member, Reports: make(map[string][]Report)}
+		for _, key := range keys {
+			log.Printf("正在采集 %s 的 %s %s...", member.Name, key, rt.Label)
+			reports, err := c.FetchMemberReports(member.ID, key)
+			if err != nil {
+				log.Printf("采集 %s 的 %s 失败: %v", member.Name, key, err)
+				mr.Failed = append(mr.Failed, key)
+				continue
+			}
+			mr.Reports[key] = filterRange(reports, r)
+		}
+		team = append(team, mr)
+	}
+
+	return keys, team, nil
+}
+
+// WriteTeamMarkdown 输出团队报告：每个成员一节，最后附合并视图；flags 为成员 ID 对应的提示（如缺失天数）
+func (c *Collector) WriteTeamMarkdown(keys []string, team []MemberReports, flags map[string]string, outputFile string) (string, error) {
+	if outputFile == "" {
+		outputFile = filepath.Join(c.getDefaultOutputDir(), "团队"+c.reportType.Label+"详情.md")
+	} else if !filepath.IsAbs(outputFile) {
+		outputFile = filepath.Join(c.getDefaultOutputDir(), outputFile)
+	}
+	f, err := fileutil.Create(outputFile, c.output)
+	if err != nil {
+		return "", err
+	}
+	defer f.Abort()
+
+	rt := c.reportType
+	fmt.Fprintf(f, "# YST 团队%s整理\n\n", rt.Label)
+	fmt.Fprintf(f, "生成时间：%s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+
+	type entry struct {
+		member string
+		report Report
+	}
+	var combined []entry
+
+	for _, mr := range team {
+		all := mr.All(keys)
+		fmt.Fprintf(f, "## %s (%d 条)\n\n", mr.Member.Name, len(all))
+		if flag := flags[mr.Member.ID]; flag != "" {
+			fmt.Fprintf(f, "> ⚠️ %s\n\n", flag)
+		}
+		if len(all) == 0 && len(mr.Failed) == 0 {
+			fmt.Fprint(f, "*暂无数据*\n\n")
+		}
+		for i, report := range all {
+			writeReport(f, i, report)
+			combined = append(combined, entry{mr.Member.Name, report})
+		}
+	}
+
+	sort.SliceStable(combined, func(i, j int) bool {
+		return combined[i].report.Date.Before(combined[j].report.Date)
+	})
+
+	fmt.Fprintf(f, "## 合并视图 (%d 条)\n\n", len(combined))
+	fmt.Fprintln(f, "| 周期 | 成员 | 内容 |")
+	fmt.Fprintln(f, "|------|------|------|")
+	for _, e := range combined {
+		text := strings.ReplaceAll(strings.ReplaceAll(e.report.Text, "\n", " "), "|", "\\|")
+		fmt.Fprintf(f, "| %s | %s | %s |\n", e.report.Period, e.member, text)
+	}
+
+	return f.Commit()
+}
+
+// filterMonths 去掉不在月份范围内的报告（日期未识别的保留）
+func filterMonths(reports []Report, months []string) []Report {
+	inRange := make(map[string]bool)
+	for _, month := range months {
+		inRange[month] = true
+	}
+
+	var filtered []Report
+	for _, r := range reports {
+		if r.Date.IsZero() || inRange[r.Date.Format("2006-01")] {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// filterRange 去掉不在日期范围内的报告（日期未识别的保留）
+func filterRange(reports []Report, r daterange.Range) []Report {
+	var filtered []Report
+	for _, report := range reports {
+		if report.Date.IsZero() || r.Contains(report.Date) {
+			filtered = append(filtered, report)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,203 @@
+package collector
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/Xuzan9396/yst_go_mcp/internal/htmlmd"
+)
+
+// 报告类型名称
+const (
+	TypeDaily   = "daily"
+	TypeWeekly  = "weekly"
+	TypeMonthly = "monthly"
+)
+
+// monthOnlyRe 匹配只有年月的文本，例如 "2025-03"、"2025年3月"
+var monthOnlyRe = regexp.MustCompile(`(\d{4})\s*[-/.年]\s*(\d{1,2})\s*月?`)
+
+// ReportType 报告类型：列表地址、选择器和周期语义
+type ReportType struct {
+	Name         string // daily / weekly / monthly
+	Label        string // 日报 / 周报 / 月报
+	ListURL      string
+	TeamListURL  string // 团队负责人查看下属报告的列表页
+	ItemSelector string
+	QueryParam   string // 列表页的周期查询参数
+	QueryLayout  string // 查询参数的时间格式，按月或按年查询
+}
+
+// reportTypes 已支持的报告类型
+var reportTypes = map[string]*ReportType{
+	TypeDaily: {
+		Name:         TypeDaily,
+		Label:        "日报",
+		ListURL:      ReportListURL,
+		TeamListURL:  BaseURL + "/report/report-daily/list",
+		ItemSelector: "#report_list li",
+		QueryParam:   "month",
+		QueryLayout:  "2006-01",
+	},
+	TypeWeekly: {
+		Name:         TypeWeekly,
+		Label:        "周报",
+		ListURL:      BaseURL + "/report/report-weekly/my-list",
+		TeamListURL:  BaseURL + "/report/report-weekly/list",
+		ItemSelector: "#report_list li",
+		QueryParam:   "month",
+		QueryLayout:  "2006-01",
+	},
+	TypeMonthly: {
+		Name:         TypeMonthly,
+		Label:        "月报",
+		ListURL:      BaseURL + "/report/report-monthly/my-list",
+		TeamListURL:  BaseURL + "/report/report-monthly/list",
+		ItemSelector: "#report_list li",
+		QueryParam:   "year",
+		QueryLayout:  "2006",
+	},
+}
+
+// GetReportType 按名称获取报告类型，名称为空时返回日报
+func GetReportType(name string) (*ReportType, error) {
+	if name == "" {
+		name = TypeDaily
+	}
+	rt, ok := reportTypes[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("不支持的报告类型: %s（可选 %s）", name, strings.Join(ReportTypeNames(), "、"))
+	}
+	return rt, nil
+}
+
+// ReportTypeNames 返回所有报告类型名称
+func ReportTypeNames() []string {
+	return []string{TypeDaily, TypeWeekly, TypeMonthly}
+}
+
+// ListPageURL 返回指定周期的列表页地址
+func (rt *ReportType) ListPageURL(query string) string {
+	return fmt.Sprintf("%s?%s=%s", rt.ListURL, rt.QueryParam, query)
+}
+
+// QueryKeys 将月份列表转换为列表页的查询周期（按年查询时去重）
+func (rt *ReportType) QueryKeys(months []string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, month := range months {
+		t, err := time.Parse("2006-01", month)
+		if err != nil {
+			continue
+		}
+		key := t.Format(rt.QueryLayout)
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// PeriodOf 返回报告所属周期：日报为日期，周报为 ISO 周，月报为月份
+func (rt *ReportType) PeriodOf(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	switch rt.Name {
+	case TypeWeekly:
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case TypeMonthly:
+		return date.Format("2006-01")
+	default:
+		return date.Format("2006-01-02")
+	}
+}
+
+// PeriodTitle 返回周期的中文标题
+func (rt *ReportType) PeriodTitle(period string) string {
+	switch rt.Name {
+	case TypeWeekly:
+		var year, week int
+		if _, err := fmt.Sscanf(period, "%d-W%d", &year, &week); err != nil {
+			return period
+		}
+		start := isoWeekStart(year, week)
+		return fmt.Sprintf("%d 年第 %d 周（%s ~ %s）", year, week,
+			start.Format("01-02"), start.AddDate(0, 0, 6).Format("01-02"))
+	case TypeMonthly:
+		return period + " 月报"
+	default:
+		return period
+	}
+}
+
+// parseList 解析列表页中的报告条目
+func (rt *ReportType) parseList(doc *goquery.Document) []Report {
+	var reports []Report
+	doc.Find(rt.ItemSelector).Each(func(i int, s *goquery.Selection) {
+		text := htmlmd.Text(s)
+		link, _ := s.Find("a").Attr("href")
+
+		if text != "" {
+			date, submittedAt := rt.parseDates(text)
+			reports = append(reports, Report{
+				Text:        text,
+				Markdown:    htmlmd.Markdown(s, doc.Url),
+				Link:        link,
+				Date:        date,
+				SubmittedAt: submittedAt,
+				Period:      rt.PeriodOf(date),
+			})
+		}
+	})
+	return reports
+}
+
+// parseDates 解析报告日期；月报只有年月时取当月第一天
+func (rt *ReportType) parseDates(text string) (time.Time, time.Time) {
+	date, submittedAt := ParseReportDates(text)
+	if date.IsZero() && rt.Name == TypeMonthly {
+		if m := monthOnlyRe.FindStringSubmatch(text); m != nil {
+			year, _ := strconv.Atoi(m[1])
+			month, _ := strconv.Atoi(m[2])
+			if month >= 1 && month <= 12 {
+				date = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+			}
+		}
+	}
+	return date, submittedAt
+}
+
+// GroupByPeriod 按报告周期重新分组，返回排好序的周期列表
+func (rt *ReportType) GroupByPeriod(reports []Report) ([]string, map[string][]Report) {
+	groups := make(map[string][]Report)
+	for _, r := range reports {
+		period := r.Period
+		if period == "" {
+			period = "未识别周期"
+		}
+		groups[period] = append(groups[period], r)
+	}
+
+	var periods []string
+	for period := range groups {
+		periods = append(periods, period)
+	}
+	sort.Strings(periods)
+	return periods, groups
+}
+
+// isoWeekStart 返回 ISO 周的周一
+func isoWeekStart(year, week int) time.Time {
+	// 1 月 4 日总是在第 1 周
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.Local)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	return jan4.AddDate(0, 0, -offset+(week-1)*7)
+}
@@ -5,14 +5,33 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/fileutil"
+	"github.com/Xuzan9396/yst_go_mcp/internal/paths"
+)
+
+// 账号配置目录下的文件
+const (
+	FileName              = "cookies.json"
+	BrowserProfileDirName = "browser_profile"
 )
 
 // Cookie 表示浏览器 Cookie
 type Cookie struct {
-	Name   string `json:"name"`
-	Value  string `json:"value"`
-	Domain string `json:"domain"`
-	Path   string `json:"path"`
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  float64 `json:"expires,omitempty"`
+	Secure   bool    `json:"secure,omitempty"`
+	HTTPOnly bool    `json:"httpOnly,omitempty"`
+}
+
+// File cookies.json 的文件格式
+type File struct {
+	SavedAt time.Time `json:"saved_at"`
+	Cookies []Cookie  `json:"cookies"`
 }
 
 // Manager Cookie 管理器
@@ -20,35 +39,13 @@ type Manager struct {
 	cookieFile string
 }
 
-// NewManager 创建 Cookie 管理器
+// NewManager 创建 Cookie 管理器，Cookie 保存在当前账号配置的目录下
 func NewManager() *Manager {
-	dataDir := getDataDir()
 	return &Manager{
-		cookieFile: filepath.Join(dataDir, "cookies.json"),
+		cookieFile: filepath.Join(paths.ProfileDir(), FileName),
 	}
 }
 
-// getDataDir 获取数据目录
-func getDataDir() string {
-	// 检查是否是打包后的可执行文件
-	exePath, err := os.Executable()
-	if err == nil {
-		// 打包后使用用户主目录
-		homeDir, err := os.UserHomeDir()
-		if err == nil {
-			dataDir := filepath.Join(homeDir, ".yst_go_mcp", "data")
-			// 检查是否在标准安装目录下运行
-			if !filepath.HasPrefix(exePath, "/tmp") && !filepath.HasPrefix(exePath, os.TempDir()) {
-				os.MkdirAll(dataDir, 0755)
-				return dataDir
-			}
-		}
-	}
-
-	// 开发模式：使用项目目录
-	return "data"
-}
-
 // SaveCookies 保存 Cookies 到文件
 func (m *Manager) SaveCookies(cookies []Cookie) error {
 	// 确保目录存在
@@ -57,12 +54,12 @@ func (m *Manager) SaveCookies(cookies []Cookie) error {
 		return fmt.Errorf("创建目录失败: %w", err)
 	}
 
-	data, err := json.MarshalIndent(cookies, "", "  ")
+	data, err := json.MarshalIndent(File{SavedAt: time.Now(), Cookies: cookies}, "", "  ")
 	if err != nil {
 		return fmt.Errorf("序列化 Cookie 失败: %w", err)
 	}
 
-	if err := os.WriteFile(m.cookieFile, data, 0600); err != nil {
+	if err := fileutil.WriteFile(m.cookieFile, data, 0600); err != nil {
 		return fmt.Errorf("保存 Cookie 文件失败: %w", err)
 	}
 
@@ -79,12 +76,17 @@ func (m *Manager) LoadCookies() ([]Cookie, error) {
 		return nil, fmt.Errorf("读取 Cookie 文件失败: %w", err)
 	}
 
-	var cookies []Cookie
-	if err := json.Unmarshal(data, &cookies); err != nil {
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		// 兼容旧版本直接保存的 Cookie 数组
+		var cookies []Cookie
+		if json.Unmarshal(data, &cookies) == nil {
+			return cookies, nil
+		}
 		return nil, fmt.Errorf("解析 Cookie 文件失败: %w", err)
 	}
 
-	return cookies, nil
+	return file.Cookies, nil
 }
 
 // HasCookies 检查是否有保存的 Cookies
@@ -104,7 +106,7 @@ func (m *Manager) ClearCookies() error {
 	}
 
 	// 同时清除浏览器配置文件目录
-	browserProfileDir := filepath.Join(filepath.Dir(m.cookieFile), "browser_profile")
+	browserProfileDir := m.GetBrowserProfileDir()
 	if _, err := os.Stat(browserProfileDir); err == nil {
 		if err := os.RemoveAll(browserProfileDir); err != nil {
 			return fmt.Errorf("删除浏览器配置目录失败: %w", err)
@@ -119,7 +121,12 @@ func (m *Manager) GetCookieFile() string {
 	return m.cookieFile
 }
 
+// GetProfileDir 获取账号配置目录
+func (m *Manager) GetProfileDir() string {
+	return filepath.Dir(m.cookieFile)
+}
+
 // GetBrowserProfileDir 获取浏览器配置目录
 func (m *Manager) GetBrowserProfileDir() string {
-	return filepath.Join(filepath.Dir(m.cookieFile), "browser_profile")
+	return filepath.Join(m.GetProfileDir(), BrowserProfileDirName)
 }
@@ -0,0 +1,286 @@
+package cookie
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 支持的 Cookie 导入导出格式
+const (
+	FormatAuto     = "auto"
+	FormatNetscape = "netscape"
+	FormatJSON     = "json"
+	FormatHeader   = "header"
+)
+
+const (
+	netscapeHeader  = "# Netscape HTTP Cookie File"
+	httpOnlyPrefix  = "#HttpOnly_"
+	defaultSitePath = "/"
+)
+
+// extensionCookie 常见浏览器插件（EditThisCookie、Cookie-Editor 等）导出的 JSON 结构
+type extensionCookie struct {
+	Name           string  `json:"name"`
+	Value          string  `json:"value"`
+	Domain         string  `json:"domain"`
+	Path           string  `json:"path"`
+	Secure         bool    `json:"secure"`
+	HTTPOnly       bool    `json:"httpOnly"`
+	ExpirationDate float64 `json:"expirationDate"`
+	Expires        float64 `json:"expires"`
+}
+
+// ParseCookies 解析 Cookie 数据，format 为 auto 时自动识别格式
+// domain 用于 header 格式补全 Domain，并过滤掉不属于该域名的 Cookie
+func ParseCookies(data, format, domain string) ([]Cookie, error) {
+	data = strings.TrimPrefix(strings.TrimSpace(data), "\ufeff")
+	if data == "" {
+		return nil, fmt.Errorf("Cookie 内容为空")
+	}
+
+	if format == "" || format == FormatAuto {
+		format = DetectFormat(data)
+	}
+
+	var cookies []Cookie
+	var err error
+	switch format {
+	case FormatNetscape:
+		cookies, err = parseNetscape(data)
+	case FormatJSON:
+		cookies, err = parseJSON(data)
+	case FormatHeader:
+		cookies, err = parseHeader(data, domain)
+	default:
+		return nil, fmt.Errorf("不支持的 Cookie 格式: %s（可选 netscape、json、header）", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cookies = FilterByDomain(cookies, domain)
+	if len(cookies) == 0 {
+		return nil, fmt.Errorf("未找到属于 %s 的 Cookie", domain)
+	}
+
+	return cookies, nil
+}
+
+// DetectFormat 根据内容特征识别 Cookie 格式
+func DetectFormat(data string) string {
+	trimmed := strings.TrimSpace(data)
+	switch {
+	case strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{"):
+		return FormatJSON
+	case strings.HasPrefix(trimmed, "#") || strings.Contains(trimmed, "\t"):
+		return FormatNetscape
+	default:
+		return FormatHeader
+	}
+}
+
+// parseNetscape 解析 Netscape cookies.txt 格式
+func parseNetscape(data string) ([]Cookie, error) {
+	var cookies []Cookie
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		httpOnly := false
+		if strings.HasPrefix(line, httpOnlyPrefix) {
+			httpOnly = true
+			line = strings.TrimPrefix(line, httpOnlyPrefix)
+		} else if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < 7 {
+			return nil, fmt.Errorf("第 %d 行格式错误：需要 7 个以 Tab 分隔的字段，实际 %d 个", lineNo, len(fields))
+		}
+
+		expires, _ := strconv.ParseFloat(fields[4], 64)
+		cookies = append(cookies, Cookie{
+			Name:     fields[5],
+			Value:    strings.Join(fields[6:], "\t"),
+			Domain:   fields[0],
+			Path:     fields[2],
+			Expires:  expires,
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			HTTPOnly: httpOnly,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取 cookies.txt 失败: %w", err)
+	}
+
+	return cookies, nil
+}
+
+// parseJSON 解析浏览器插件导出的 JSON（数组或 {"cookies": [...]}）
+func parseJSON(data string) ([]Cookie, error) {
+	var items []extensionCookie
+	if strings.HasPrefix(data, "{") {
+		var wrapper struct {
+			Cookies []extensionCookie `json:"cookies"`
+		}
+		if err := json.Unmarshal([]byte(data), &wrapper); err != nil {
+			return nil, fmt.Errorf("解析 JSON 失败: %w", err)
+		}
+		items = wrapper.Cookies
+	} else if err := json.Unmarshal([]byte(data), &items); err != nil {
+		return nil, fmt.Errorf("解析 JSON 失败: %w", err)
+	}
+
+	var cookies []Cookie
+	for _, item := range items {
+		if item.Name == "" {
+			continue
+		}
+		expires := item.ExpirationDate
+		if expires == 0 {
+			expires = item.Expires
+		}
+		cookies = append(cookies, Cookie{
+			Name:     item.Name,
+			Value:    item.Value,
+			Domain:   item.Domain,
+			Path:     item.Path,
+			Expires:  expires,
+			Secure:   item.Secure,
+			HTTPOnly: item.HTTPOnly,
+		})
+	}
+
+	return cookies, nil
+}
+
+// parseHeader 解析原始 Cookie 请求头，例如 "Cookie: a=1; b=2"
+func parseHeader(data, domain string) ([]Cookie, error) {
+	line := strings.TrimSpace(strings.SplitN(data, "\n", 2)[0])
+	if idx := strings.Index(line, ":"); idx >= 0 && strings.EqualFold(strings.TrimSpace(line[:idx]), "cookie") {
+		line = line[idx+1:]
+	}
+
+	var cookies []Cookie
+	for _, part := range strings.Split(line, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("Cookie 头格式错误: %q", part)
+		}
+		cookies = append(cookies, Cookie{
+			Name:   strings.TrimSpace(name),
+			Value:  strings.TrimSpace(value),
+			Domain: domain,
+			Path:   defaultSitePath,
+		})
+	}
+
+	return cookies, nil
+}
+
+// FilterByDomain 过滤出适用于指定域名的 Cookie，domain 为空时不过滤
+func FilterByDomain(cookies []Cookie, domain string) []Cookie {
+	if domain == "" {
+		return cookies
+	}
+
+	var result []Cookie
+	for _, ck := range cookies {
+		d := strings.TrimPrefix(ck.Domain, ".")
+		if d == "" {
+			ck.Domain = domain
+			d = domain
+		}
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			if ck.Path == "" {
+				ck.Path = defaultSitePath
+			}
+			result = append(result, ck)
+		}
+	}
+
+	return result
+}
+
+// FormatCookies 将 Cookie 导出为指定格式的文本
+func FormatCookies(cookies []Cookie, format string) (string, error) {
+	switch format {
+	case "", FormatJSON:
+		var items []extensionCookie
+		for _, ck := range cookies {
+			items = append(items, extensionCookie{
+				Name:           ck.Name,
+				Value:          ck.Value,
+				Domain:         ck.Domain,
+				Path:           ck.Path,
+				Secure:         ck.Secure,
+				HTTPOnly:       ck.HTTPOnly,
+				ExpirationDate: ck.Expires,
+			})
+		}
+		data, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("序列化 Cookie 失败: %w", err)
+		}
+		return string(data), nil
+
+	case FormatNetscape:
+		var b strings.Builder
+		b.WriteString(netscapeHeader + "\n")
+		fmt.Fprintf(&b, "# 由 yst-go-mcp 导出于 %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+		for _, ck := range cookies {
+			domain := ck.Domain
+			if ck.HTTPOnly {
+				domain = httpOnlyPrefix + domain
+			}
+			path := ck.Path
+			if path == "" {
+				path = defaultSitePath
+			}
+			fmt.Fprintf(&b, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+				domain,
+				netscapeBool(strings.HasPrefix(ck.Domain, ".")),
+				path,
+				netscapeBool(ck.Secure),
+				int64(ck.Expires),
+				ck.Name,
+				ck.Value,
+			)
+		}
+		return b.String(), nil
+
+	case FormatHeader:
+		var parts []string
+		for _, ck := range cookies {
+			parts = append(parts, ck.Name+"="+ck.Value)
+		}
+		return "Cookie: " + strings.Join(parts, "; "), nil
+
+	default:
+		return "", fmt.Errorf("不支持的导出格式: %s（可选 netscape、json、header）", format)
+	}
+}
+
+// netscapeBool 转换为 cookies.txt 使用的 TRUE/FALSE
+func netscapeBool(v bool) string {
+	if v {
+		return "TRUE"
+	}
+	return "FALSE"
+}
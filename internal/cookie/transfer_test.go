@@ -0,0 +1,148 @@
+package cookie
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDomain = "kpi.example.com"
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`[{"name": "a"}]`, FormatJSON},
+		{` {"cookies": []}`, FormatJSON},
+		{"# Netscape HTTP Cookie File\n", FormatNetscape},
+		{".example.com\tTRUE\t/\tFALSE\t0\ta\t1", FormatNetscape},
+		{"Cookie: a=1; b=2", FormatHeader},
+		{"a=1; b=2", FormatHeader},
+	}
+
+	for _, tt := range tests {
+		if got := DetectFormat(tt.data); got != tt.want {
+			t.Errorf("DetectFormat(%q) = %s，期望 %s", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestParseCookies(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format string
+		want   []Cookie
+	}{
+		{
+			name: "Netscape",
+			data: "\ufeff# Netscape HTTP Cookie File\n\n" +
+				".example.com\tTRUE\t/\tTRUE\t1767225600\tsession\tabc\n" +
+				"#HttpOnly_kpi.example.com\tFALSE\t/report\tFALSE\t0\ttoken\tx\ty\r\n" +
+				"other.com\tFALSE\t/\tFALSE\t0\tfoo\tbar\n",
+			want: []Cookie{
+				{Name: "session", Value: "abc", Domain: ".example.com", Path: "/", Expires: 1767225600, Secure: true},
+				{Name: "token", Value: "x\ty", Domain: "kpi.example.com", Path: "/report", HTTPOnly: true},
+			},
+		},
+		{
+			name: "JSON 数组",
+			data: `[{"name": "session", "value": "abc", "domain": ".example.com", "path": "/", "secure": true, "httpOnly": true, "expirationDate": 1767225600.5},
+				{"name": "", "value": "ignored", "domain": "kpi.example.com"},
+				{"name": "foo", "value": "bar", "domain": "other.com"}]`,
+			want: []Cookie{
+				{Name: "session", Value: "abc", Domain: ".example.com", Path: "/", Expires: 1767225600.5, Secure: true, HTTPOnly: true},
+			},
+		},
+		{
+			name: "JSON 对象",
+			data: `{"cookies": [{"name": "token", "value": "1", "domain": "", "expires": 100}]}`,
+			want: []Cookie{
+				{Name: "token", Value: "1", Domain: testDomain, Path: "/", Expires: 100},
+			},
+		},
+		{
+			name: "请求头",
+			data: "Cookie: session=abc; token = x=y ;\nsecond line",
+			want: []Cookie{
+				{Name: "session", Value: "abc", Domain: testDomain, Path: "/"},
+				{Name: "token", Value: "x=y", Domain: testDomain, Path: "/"},
+			},
+		},
+		{
+			name:   "指定格式",
+			data:   "session=abc",
+			format: FormatHeader,
+			want: []Cookie{
+				{Name: "session", Value: "abc", Domain: testDomain, Path: "/"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCookies(tt.data, tt.format, testDomain)
+			if err != nil {
+				t.Fatalf("ParseCookies() 出错: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCookies()\n得到：%+v\n期望：%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCookiesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format string
+		want   string
+	}{
+		{"空内容", "  \n", "", "内容为空"},
+		{"未知格式", "a=1", "xml", "不支持的 Cookie 格式"},
+		{"Netscape 字段不足", "kpi.example.com\tFALSE\t/\tFALSE\t0\ta", "", "第 1 行格式错误"},
+		{"JSON 格式错误", `[{"name": }]`, "", "解析 JSON 失败"},
+		{"请求头格式错误", "Cookie: a=1; novalue", "", "Cookie 头格式错误"},
+		{"没有当前域名的 Cookie", "other.com\tFALSE\t/\tFALSE\t0\ta\t1", "", "未找到属于"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCookies(tt.data, tt.format, testDomain)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseCookies() 错误 = %v，期望包含 %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// 导出后再导入应得到相同的 Cookie
+func TestFormatCookiesRoundTrip(t *testing.T) {
+	cookies := []Cookie{
+		{Name: "session", Value: "abc", Domain: ".example.com", Path: "/", Expires: 1767225600, Secure: true},
+		{Name: "token", Value: "x", Domain: "kpi.example.com", Path: "/report", HTTPOnly: true},
+	}
+
+	for _, format := range []string{FormatJSON, FormatNetscape} {
+		t.Run(format, func(t *testing.T) {
+			data, err := FormatCookies(cookies, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ParseCookies(data, FormatAuto, testDomain)
+			if err != nil {
+				t.Fatalf("重新导入失败: %v\n%s", err, data)
+			}
+			if !reflect.DeepEqual(got, cookies) {
+				t.Errorf("重新导入\n得到：%+v\n期望：%+v", got, cookies)
+			}
+		})
+	}
+
+	header, err := FormatCookies(cookies, FormatHeader)
+	if err != nil || header != "Cookie: session=abc; token=x" {
+		t.Errorf("FormatCookies(header) = %q, %v", header, err)
+	}
+}
@@ -0,0 +1,293 @@
+package daterange
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	// 内置时区数据，Windows 等缺少 zoneinfo 的系统也能解析时区名称
+	_ "time/tzdata"
+)
+
+// TimezoneEnv 指定解析日期时使用的时区，例如 Asia/Shanghai
+const TimezoneEnv = "YST_TIMEZONE"
+
+// 日期格式
+const (
+	DateLayout  = "2006-01-02"
+	MonthLayout = "2006-01"
+)
+
+// MaxMonths 单次查询允许覆盖的最大月份数，避免误写年份导致长时间采集
+const MaxMonths = 36
+
+var (
+	dateRe    = regexp.MustCompile(`^(\d{4})-(\d{1,2})-(\d{1,2})$`)
+	monthRe   = regexp.MustCompile(`^(\d{4})-(\d{1,2})$`)
+	compactRe = regexp.MustCompile(`^(\d{4})(\d{2})(\d{2})?$`)
+	yearRe    = regexp.MustCompile(`^(\d{4})$`)
+	weekRe    = regexp.MustCompile(`^(\d{4})-?w(\d{1,2})$`)
+	quarterRe = regexp.MustCompile(`^(\d{4})-?q([1-4])$`)
+	lastNRe   = regexp.MustCompile(`^(?:last|past)\s*(\d+)\s*days?$|^(?:最近|近|过去)\s*(\d+)\s*天$`)
+	rangeSep  = regexp.MustCompile(`\s*(?:~|～|\s+to\s+|至|到|\s+-\s+|—)\s*`)
+)
+
+// Range 按天计算的闭区间，Start 和 End 都是当天零点
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Location 返回解析日期使用的时区：优先使用参数，其次环境变量 YST_TIMEZONE，最后为本地时区
+func Location(name string) (*time.Location, error) {
+	if name == "" {
+		name = os.Getenv(TimezoneEnv)
+	}
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("无效的时区 %q，例如 Asia/Shanghai", name)
+	}
+	return loc, nil
+}
+
+// Parse 解析一个日期表达式，支持：
+//   - 日期 2025-03-10、月份 2025-03、年份 2025，也接受 2025-3、2025/03、202503、2025年3月、2025年3月10日 等写法
+//   - ISO 周 2025-W11、季度 2025-Q1
+//   - 相对表达：今天、昨天、本周、上周、本月、上个月、本季度、上季度、今年、去年、最近 30 天、last 30 days
+//   - 用 ~、至、到、to 连接的两个表达式，例如 2025-03-10~2025-03-21、上个月~本月
+//
+// now 决定相对表达的基准日期和时区
+func Parse(expr string, now time.Time) (Range, error) {
+	expr = strings.TrimSpace(expr)
+	if parts := rangeSep.Split(expr, 2); len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+		return Between(parts[0], parts[1], now)
+	}
+	r, err := parseSingle(expr, now)
+	if err != nil {
+		return Range{}, err
+	}
+	return r, r.validate()
+}
+
+// Between 以 start 表达式的起点和 end 表达式的终点组成范围，end 为空时等同于 Parse(start)
+func Between(start, end string, now time.Time) (Range, error) {
+	from, err := parseSingle(start, now)
+	if err != nil {
+		return Range{}, fmt.Errorf("起始%w", err)
+	}
+	if strings.TrimSpace(end) == "" {
+		return from, from.validate()
+	}
+	to, err := parseSingle(end, now)
+	if err != nil {
+		return Range{}, fmt.Errorf("结束%w", err)
+	}
+
+	r := Range{Start: from.Start, End: to.End}
+	if r.Start.After(r.End) {
+		return Range{}, fmt.Errorf("起始 %s（%s）晚于结束 %s（%s），是否写反了？",
+			strings.TrimSpace(start), from.Start.Format(DateLayout), strings.TrimSpace(end), to.End.Format(DateLayout))
+	}
+	return r, r.validate()
+}
+
+// validate 检查范围是否过长
+func (r Range) validate() error {
+	if n := len(r.Months()); n > MaxMonths {
+		return fmt.Errorf("范围 %s 覆盖 %d 个月，超过上限 %d 个月，请缩小范围或分批查询", r, n, MaxMonths)
+	}
+	return nil
+}
+
+// Months 解析起止月份并返回其间的所有月份（YYYY-MM），用于只按月份工作的场景
+func Months(startMonth, endMonth string) ([]string, error) {
+	r, err := Between(startMonth, endMonth, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return r.Months(), nil
+}
+
+// ParseDay 解析单个日期，例如 2025-03-10、2025/3/10、20250310、今天、昨天
+func ParseDay(expr string, now time.Time) (time.Time, error) {
+	r, err := parseSingle(expr, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !r.Start.Equal(r.End) {
+		return time.Time{}, fmt.Errorf("需要具体日期，%q 对应 %s", expr, r)
+	}
+	return r.Start, nil
+}
+
+// normalize 统一日期写法：2025/3/10、2025.03、2025年3月10日 -> 2025-3-10，202503 -> 2025-03
+func normalize(s string) string {
+	s = strings.NewReplacer("/", "-", ".", "-", "年", "-", "月", "-", "日", "", "号", "", " ", "").Replace(s)
+	s = strings.Trim(s, "-")
+	if m := compactRe.FindStringSubmatch(s); m != nil {
+		if m[3] != "" {
+			return m[1] + "-" + m[2] + "-" + m[3]
+		}
+		return m[1] + "-" + m[2]
+	}
+	return s
+}
+
+// parseSingle 解析不含区间分隔符的单个表达式
+func parseSingle(expr string, now time.Time) (Range, error) {
+	loc := now.Location()
+	today := day(now.Year(), now.Month(), now.Day(), loc)
+	s := strings.ToLower(strings.TrimSpace(expr))
+
+	switch s {
+	case "":
+		return Range{}, fmt.Errorf("日期不能为空")
+	case "今天", "today":
+		return Range{today, today}, nil
+	case "昨天", "yesterday":
+		d := today.AddDate(0, 0, -1)
+		return Range{d, d}, nil
+	case "本周", "这周", "this week":
+		return weekOf(today, 0), nil
+	case "上周", "last week":
+		return weekOf(today, -1), nil
+	case "本月", "这个月", "this month":
+		return monthOf(today.Year(), today.Month(), loc), nil
+	case "上个月", "上月", "last month":
+		return monthOf(today.Year(), today.Month()-1, loc), nil
+	case "本季度", "this quarter":
+		return quarterOf(today.Year(), quarter(today.Month()), loc), nil
+	case "上季度", "上个季度", "last quarter":
+		return quarterOf(today.Year(), quarter(today.Month())-1, loc), nil
+	case "今年", "this year":
+		return yearOf(today.Year(), loc), nil
+	case "去年", "last year":
+		return yearOf(today.Year()-1, loc), nil
+	}
+
+	if m := lastNRe.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1] + m[2])
+		if n < 1 {
+			return Range{}, fmt.Errorf("天数必须大于 0: %q", expr)
+		}
+		return Range{today.AddDate(0, 0, -(n - 1)), today}, nil
+	}
+
+	s = normalize(s)
+
+	if m := dateRe.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		d, _ := strconv.Atoi(m[3])
+		t := day(year, time.Month(month), d, loc)
+		if month < 1 || month > 12 || t.Day() != d {
+			return Range{}, fmt.Errorf("日期无效 %q，%d 年 %d 月没有 %d 日", expr, year, month, d)
+		}
+		return Range{t, t}, nil
+	}
+
+	if m := monthRe.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		if month < 1 || month > 12 {
+			return Range{}, fmt.Errorf("月份无效 %q，月份应在 1-12 之间", expr)
+		}
+		return monthOf(year, time.Month(month), loc), nil
+	}
+
+	if m := weekRe.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		monday := isoWeekStart(year, week, loc)
+		if y, w := monday.ISOWeek(); week < 1 || y != year || w != week {
+			return Range{}, fmt.Errorf("周数无效 %q，%d 年没有第 %d 周", expr, year, week)
+		}
+		return Range{monday, monday.AddDate(0, 0, 6)}, nil
+	}
+
+	if m := quarterRe.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		q, _ := strconv.Atoi(m[2])
+		return quarterOf(year, q, loc), nil
+	}
+
+	if m := yearRe.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		return yearOf(year, loc), nil
+	}
+
+	return Range{}, fmt.Errorf("日期无法识别 %q，支持 2025-03、2025-3、2025/03、202503、2025年3月、2025-03-10、2025-W11、2025-Q1、本周、上个月、最近 30 天等", expr)
+}
+
+// Months 返回范围覆盖的所有月份（YYYY-MM）
+func (r Range) Months() []string {
+	var months []string
+	end := r.End.Format(MonthLayout)
+	for m := day(r.Start.Year(), r.Start.Month(), 1, r.Start.Location()); m.Format(MonthLayout) <= end; m = m.AddDate(0, 1, 0) {
+		months = append(months, m.Format(MonthLayout))
+	}
+	return months
+}
+
+// Contains 判断日期是否在范围内（只比较年月日）
+func (r Range) Contains(t time.Time) bool {
+	key := t.Format(DateLayout)
+	return key >= r.Start.Format(DateLayout) && key <= r.End.Format(DateLayout)
+}
+
+// Days 返回范围内的天数
+func (r Range) Days() int {
+	return int(r.End.Sub(r.Start).Hours()/24+0.5) + 1
+}
+
+// String 返回 "YYYY-MM-DD ~ YYYY-MM-DD"
+func (r Range) String() string {
+	return r.Start.Format(DateLayout) + " ~ " + r.End.Format(DateLayout)
+}
+
+// day 构造某天零点，月份和日期溢出时自动进位
+func day(year int, month time.Month, d int, loc *time.Location) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, loc)
+}
+
+// weekOf 返回 date 所在周（周一到周日）偏移 offset 周后的范围
+func weekOf(date time.Time, offset int) Range {
+	monday := date.AddDate(0, 0, -(int(date.Weekday())+6)%7+offset*7)
+	return Range{monday, monday.AddDate(0, 0, 6)}
+}
+
+// monthOf 返回整月范围
+func monthOf(year int, month time.Month, loc *time.Location) Range {
+	start := day(year, month, 1, loc)
+	return Range{start, start.AddDate(0, 1, -1)}
+}
+
+// quarterOf 返回季度范围，q 可以越界（0 表示上一年第 4 季度）
+func quarterOf(year, q int, loc *time.Location) Range {
+	start := day(year, time.Month((q-1)*3+1), 1, loc)
+	return Range{start, start.AddDate(0, 3, -1)}
+}
+
+// yearOf 返回整年范围
+func yearOf(year int, loc *time.Location) Range {
+	return Range{day(year, 1, 1, loc), day(year, 12, 31, loc)}
+}
+
+// quarter 返回月份所在季度
+func quarter(m time.Month) int {
+	return (int(m)-1)/3 + 1
+}
+
+// isoWeekStart 返回 ISO 周的周一
+func isoWeekStart(year, week int, loc *time.Location) time.Time {
+	// 1 月 4 日总是在第 1 周
+	jan4 := day(year, 1, 4, loc)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	return jan4.AddDate(0, 0, -offset+(week-1)*7)
+}
@@ -0,0 +1,170 @@
+package daterange
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// 2025-03-12 是周三
+	now := time.Date(2025, 3, 12, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		expr       string
+		start, end string
+	}{
+		{"2025-03-10", "2025-03-10", "2025-03-10"},
+		{"2025-03", "2025-03-01", "2025-03-31"},
+		{"2024-02", "2024-02-01", "2024-02-29"},
+		{"2025", "2025-01-01", "2025-12-31"},
+		{"2025-3", "2025-03-01", "2025-03-31"},
+		{"2025/03", "2025-03-01", "2025-03-31"},
+		{"2025.3.10", "2025-03-10", "2025-03-10"},
+		{"202503", "2025-03-01", "2025-03-31"},
+		{"20250310", "2025-03-10", "2025-03-10"},
+		{"2025年3月", "2025-03-01", "2025-03-31"},
+		{"2025年3月10日", "2025-03-10", "2025-03-10"},
+		{"2025-W11", "2025-03-10", "2025-03-16"},
+		{"2025w1", "2024-12-30", "2025-01-05"},
+		{"2020-W53", "2020-12-28", "2021-01-03"},
+		{"2025-Q1", "2025-01-01", "2025-03-31"},
+		{"2025q4", "2025-10-01", "2025-12-31"},
+		{"今天", "2025-03-12", "2025-03-12"},
+		{"yesterday", "2025-03-11", "2025-03-11"},
+		{"本周", "2025-03-10", "2025-03-16"},
+		{"上周", "2025-03-03", "2025-03-09"},
+		{"本月", "2025-03-01", "2025-03-31"},
+		{"上个月", "2025-02-01", "2025-02-28"},
+		{"本季度", "2025-01-01", "2025-03-31"},
+		{"上季度", "2024-10-01", "2024-12-31"},
+		{"去年", "2024-01-01", "2024-12-31"},
+		{"最近 7 天", "2025-03-06", "2025-03-12"},
+		{"last 30 days", "2025-02-11", "2025-03-12"},
+		{"2025-03-10~2025-03-21", "2025-03-10", "2025-03-21"},
+		{"2025-01 至 2025-03", "2025-01-01", "2025-03-31"},
+		{"上个月~本月", "2025-02-01", "2025-03-31"},
+		{"2025-03-01 to 2025-03-05", "2025-03-01", "2025-03-05"},
+		{"2025-03-01 - 2025-03-05", "2025-03-01", "2025-03-05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			r, err := Parse(tt.expr, now)
+			if err != nil {
+				t.Fatalf("Parse(%q) 出错: %v", tt.expr, err)
+			}
+			if got, want := r.String(), tt.start+" ~ "+tt.end; got != want {
+				t.Errorf("Parse(%q) = %s，期望 %s", tt.expr, got, want)
+			}
+			if r.Start.Location() != time.UTC {
+				t.Errorf("Parse(%q) 时区 = %s，期望与 now 相同", tt.expr, r.Start.Location())
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	now := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want string // 错误信息中应包含的内容
+	}{
+		{"", "不能为空"},
+		{"2025-13", "月份无效"},
+		{"2025-02-30", "没有 30 日"},
+		{"2025-W53", "没有第 53 周"},
+		{"最近 0 天", "必须大于 0"},
+		{"下个月", "无法识别"},
+		{"2025-03~2025-01", "是否写反了"},
+		{"2025-03~abc", "结束日期无法识别"},
+		{"2020~2025", "超过上限"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr, now)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse(%q) 错误 = %v，期望包含 %q", tt.expr, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"2025/3/10", "2025-3-10"},
+		{"2025.03", "2025-03"},
+		{"2025年3月10日", "2025-3-10"},
+		{"2025年3月", "2025-3"},
+		{"2025 年 3 月 10 号", "2025-3-10"},
+		{"202503", "2025-03"},
+		{"20250310", "2025-03-10"},
+		{"2025-w11", "2025-w11"},
+	}
+
+	for _, tt := range tests {
+		if got := normalize(tt.in); got != tt.want {
+			t.Errorf("normalize(%q) = %q，期望 %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseDay(t *testing.T) {
+	now := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	if d, err := ParseDay("2025/3/10", now); err != nil || d.Format(DateLayout) != "2025-03-10" {
+		t.Errorf("ParseDay(2025/3/10) = %v, %v", d, err)
+	}
+	if _, err := ParseDay("2025-03", now); err == nil {
+		t.Error("ParseDay(2025-03) 应要求具体日期")
+	}
+}
+
+func TestRange(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	r, err := Parse("2025-01-30~2025-03-02", time.Date(2025, 3, 12, 0, 0, 0, 0, loc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(r.Months(), ","); got != "2025-01,2025-02,2025-03" {
+		t.Errorf("Months() = %s", got)
+	}
+	if got := r.Days(); got != 32 {
+		t.Errorf("Days() = %d，期望 32", got)
+	}
+
+	// Contains 只比较年月日，不受时刻和时区影响
+	tests := []struct {
+		t    time.Time
+		want bool
+	}{
+		{time.Date(2025, 1, 30, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 3, 2, 23, 59, 0, 0, time.UTC), true},
+		{time.Date(2025, 1, 29, 23, 59, 0, 0, loc), false},
+		{time.Date(2025, 3, 3, 0, 0, 0, 0, loc), false},
+	}
+	for _, tt := range tests {
+		if got := r.Contains(tt.t); got != tt.want {
+			t.Errorf("Contains(%s) = %v，期望 %v", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestLocation(t *testing.T) {
+	t.Setenv(TimezoneEnv, "Asia/Tokyo")
+
+	if loc, err := Location(""); err != nil || loc.String() != "Asia/Tokyo" {
+		t.Errorf("Location(\"\") = %v, %v，期望使用环境变量", loc, err)
+	}
+	if loc, err := Location("UTC"); err != nil || loc.String() != "UTC" {
+		t.Errorf("Location(UTC) = %v, %v，期望参数优先", loc, err)
+	}
+	if _, err := Location("Mars/Olympus"); err == nil {
+		t.Error("Location(Mars/Olympus) 应返回错误")
+	}
+}
@@ -0,0 +1,263 @@
+package docx
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/xmlutil"
+)
+
+// 正文使用的段落样式 ID，套用模板时会映射为模板中同名样式的 ID
+const (
+	StyleTitle    = "Title"
+	StyleHeading1 = "Heading1"
+	StyleHeading2 = "Heading2"
+)
+
+// styleNames 样式 ID 对应的内置样式名（Word 中文版的样式 ID 可能是 "1"、"2"，但名称固定）
+var styleNames = map[string]string{
+	StyleTitle:    "title",
+	StyleHeading1: "heading 1",
+	StyleHeading2: "heading 2",
+}
+
+// pageWidth A4 纸去掉左右页边距后的宽度（单位 1/20 磅）
+const pageWidth = 9026
+
+// Document Word 文档
+type Document struct {
+	body   []string
+	Header string // 页眉文字
+	Footer string // 页脚文字，后面自动加页码
+}
+
+// New 创建空文档
+func New() *Document {
+	return &Document{}
+}
+
+// Title 添加文档标题
+func (d *Document) Title(text string) {
+	d.body = append(d.body, paragraph(StyleTitle, "center", run(text, false)))
+}
+
+// Heading 添加标题，level 为 1 或 2
+func (d *Document) Heading(level int, text string) {
+	style := StyleHeading1
+	if level > 1 {
+		style = StyleHeading2
+	}
+	d.body = append(d.body, paragraph(style, "", run(text, false)))
+}
+
+// Paragraph 添加正文段落，文本中的换行会保留
+func (d *Document) Paragraph(text string) {
+	d.body = append(d.body, paragraph("", "", run(text, false)))
+}
+
+// Centered 添加居中段落
+func (d *Document) Centered(text string) {
+	d.body = append(d.body, paragraph("", "center", run(text, false)))
+}
+
+// Bullet 添加列表项
+func (d *Document) Bullet(text string) {
+	d.body = append(d.body, `<w:p><w:pPr><w:ind w:left="420" w:hanging="420"/></w:pPr>`+run("•\t"+text, false)+`</w:p>`)
+}
+
+// Table 添加表格，widths 为各列宽度占比，bold 为需要加粗的行（例如合计行）
+func (d *Document) Table(header []string, rows [][]string, widths []float64, bold map[int]bool) {
+	cols := make([]int, len(header))
+	total := 0.0
+	for _, w := range widths {
+		total += w
+	}
+	for i := range cols {
+		if i < len(widths) && total > 0 {
+			cols[i] = int(widths[i] / total * pageWidth)
+		} else {
+			cols[i] = pageWidth / len(header)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(`<w:tbl><w:tblPr><w:tblW w:w="5000" w:type="pct"/><w:tblBorders>`)
+	for _, side := range []string{"top", "left", "bottom", "right", "insideH", "insideV"} {
+		fmt.Fprintf(&b, `<w:%s w:val="single" w:sz="4" w:space="0" w:color="auto"/>`, side)
+	}
+	b.WriteString(`</w:tblBorders><w:tblLayout w:type="fixed"/><w:tblCellMar><w:left w:w="108" w:type="dxa"/><w:right w:w="108" w:type="dxa"/></w:tblCellMar></w:tblPr><w:tblGrid>`)
+	for _, w := range cols {
+		fmt.Fprintf(&b, `<w:gridCol w:w="%d"/>`, w)
+	}
+	b.WriteString(`</w:tblGrid>`)
+
+	writeRow := func(cells []string, isHeader, isBold bool) {
+		b.WriteString(`<w:tr>`)
+		if isHeader {
+			b.WriteString(`<w:trPr><w:tblHeader/></w:trPr>`)
+		}
+		for i, w := range cols {
+			text := ""
+			if i < len(cells) {
+				text = cells[i]
+			}
+			fmt.Fprintf(&b, `<w:tc><w:tcPr><w:tcW w:w="%d" w:type="dxa"/>`, w)
+			align := ""
+			if isHeader {
+				b.WriteString(`<w:shd w:val="clear" w:color="auto" w:fill="D9E1F2"/>`)
+				align = "center"
+			}
+			b.WriteString(`<w:vAlign w:val="center"/></w:tcPr>`)
+			b.WriteString(paragraph("", align, run(text, isHeader || isBold)))
+			b.WriteString(`</w:tc>`)
+		}
+		b.WriteString(`</w:tr>`)
+	}
+
+	writeRow(header, true, false)
+	for i, row := range rows {
+		writeRow(row, false, bold[i])
+	}
+	b.WriteString(`</w:tbl>`)
+
+	// 表格后必须跟一个段落，否则相邻表格会粘在一起
+	d.body = append(d.body, b.String(), `<w:p/>`)
+}
+
+// Write 以 DOCX（Office Open XML）格式写出文档
+func (d *Document) Write(w io.Writer) error {
+	files := []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypes},
+		{"_rels/.rels", rootRels},
+		{"word/_rels/document.xml.rels", documentRels},
+		{"word/document.xml", d.documentXML()},
+		{"word/styles.xml", stylesXML},
+		{"word/header1.xml", headerXML("hdr", paragraph("Header", "center", run(d.Header, false)))},
+		{"word/footer1.xml", headerXML("ftr", footerParagraph(d.Footer))},
+	}
+
+	z := zip.NewWriter(w)
+	for _, f := range files {
+		fw, err := z.Create(f.name)
+		if err != nil {
+			return fmt.Errorf("写入 %s 失败: %w", f.name, err)
+		}
+		if _, err := io.WriteString(fw, f.content); err != nil {
+			return fmt.Errorf("写入 %s 失败: %w", f.name, err)
+		}
+	}
+	return z.Close()
+}
+
+// documentXML 生成 word/document.xml
+func (d *Document) documentXML() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<w:document ` + namespaces + `><w:body>`)
+	b.WriteString(strings.Join(d.body, ""))
+	b.WriteString(`<w:sectPr><w:headerReference w:type="default" r:id="rId2"/><w:footerReference w:type="default" r:id="rId3"/>`)
+	b.WriteString(`<w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="851" w:footer="992" w:gutter="0"/></w:sectPr>`)
+	b.WriteString(`</w:body></w:document>`)
+	return b.String()
+}
+
+// paragraph 生成段落，style 和 align 可为空
+func paragraph(style, align, runs string) string {
+	var b strings.Builder
+	b.WriteString(`<w:p>`)
+	if style != "" || align != "" {
+		b.WriteString(`<w:pPr>`)
+		if style != "" {
+			fmt.Fprintf(&b, `<w:pStyle w:val="%s"/>`, style)
+		}
+		if align != "" {
+			fmt.Fprintf(&b, `<w:jc w:val="%s"/>`, align)
+		}
+		b.WriteString(`</w:pPr>`)
+	}
+	b.WriteString(runs)
+	b.WriteString(`</w:p>`)
+	return b.String()
+}
+
+// run 生成文字，换行转为 <w:br/>，制表符转为 <w:tab/>
+func run(text string, bold bool) string {
+	var b strings.Builder
+	b.WriteString(`<w:r>`)
+	if bold {
+		b.WriteString(`<w:rPr><w:b/></w:rPr>`)
+	}
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			b.WriteString(`<w:br/>`)
+		}
+		for j, part := range strings.Split(line, "\t") {
+			if j > 0 {
+				b.WriteString(`<w:tab/>`)
+			}
+			if part != "" {
+				fmt.Fprintf(&b, `<w:t xml:space="preserve">%s</w:t>`, xmlutil.Escape(part))
+			}
+		}
+	}
+	b.WriteString(`</w:r>`)
+	return b.String()
+}
+
+// footerParagraph 页脚：文字 + 第 N 页
+func footerParagraph(text string) string {
+	if text != "" {
+		text += "　　"
+	}
+	runs := run(text+"第 ", false) +
+		`<w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> PAGE </w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>1</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r>` +
+		run(" 页", false)
+	return paragraph("Footer", "center", runs)
+}
+
+// headerXML 生成页眉（hdr）或页脚（ftr）
+func headerXML(tag, content string) string {
+	return xmlHeader + `<w:` + tag + ` ` + namespaces + `>` + content + `</w:` + tag + `>`
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const namespaces = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+
+const contentTypes = xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`<Override PartName="/word/header1.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml"/>` +
+	`<Override PartName="/word/footer1.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml"/>` +
+	`</Types>`
+
+const rootRels = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+	`</Relationships>`
+
+const documentRels = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/header" Target="header1.xml"/>` +
+	`<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer" Target="footer1.xml"/>` +
+	`</Relationships>`
+
+// stylesXML 默认样式：正文宋体五号，标题黑体
+const stylesXML = xmlHeader + `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:eastAsia="宋体" w:cs="Times New Roman"/>` +
+	`<w:sz w:val="21"/><w:szCs w:val="21"/><w:lang w:val="en-US" w:eastAsia="zh-CN"/></w:rPr></w:rPrDefault>` +
+	`<w:pPrDefault><w:pPr><w:spacing w:after="120" w:line="360" w:lineRule="auto"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+	`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:jc w:val="center"/><w:spacing w:before="240" w:after="240"/></w:pPr><w:rPr><w:rFonts w:eastAsia="黑体"/><w:b/><w:sz w:val="36"/><w:szCs w:val="36"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:rFonts w:eastAsia="黑体"/><w:b/><w:sz w:val="30"/><w:szCs w:val="30"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:keepNext/><w:spacing w:before="180" w:after="60"/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:rFonts w:eastAsia="黑体"/><w:b/><w:sz w:val="26"/><w:szCs w:val="26"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Header"><w:name w:val="header"/><w:basedOn w:val="Normal"/><w:pPr><w:pBdr><w:bottom w:val="single" w:sz="4" w:space="1" w:color="auto"/></w:pBdr><w:jc w:val="center"/></w:pPr><w:rPr><w:sz w:val="18"/><w:szCs w:val="18"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Footer"><w:name w:val="footer"/><w:basedOn w:val="Normal"/><w:pPr><w:jc w:val="center"/></w:pPr><w:rPr><w:sz w:val="18"/><w:szCs w:val="18"/></w:rPr></w:style>` +
+	`</w:styles>`
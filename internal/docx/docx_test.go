@@ -0,0 +1,201 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		bold bool
+		want string
+	}{
+		{"普通文字", "修复登录", false, `<w:r><w:t xml:space="preserve">修复登录</w:t></w:r>`},
+		{"加粗", "合计", true, `<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">合计</w:t></w:r>`},
+		{"换行和制表符", "a\tb\nc", false, `<w:r><w:t xml:space="preserve">a</w:t><w:tab/><w:t xml:space="preserve">b</w:t><w:br/><w:t xml:space="preserve">c</w:t></w:r>`},
+		{"转义", `<a & "b">`, false, `<w:r><w:t xml:space="preserve">&lt;a &amp; &quot;b&quot;&gt;</w:t></w:r>`},
+		{"空文字", "", false, `<w:r></w:r>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := run(tt.text, tt.bold); got != tt.want {
+				t.Errorf("run(%q)\n得到：%s\n期望：%s", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	d := New()
+	d.Header = "张三 2025-03"
+	d.Footer = "工作总结"
+	d.Title("2025 年 3 月工作总结")
+	d.Heading(1, "一、主要工作")
+	d.Table([]string{"序号", "任务", "权重"}, [][]string{{"1", "登录 & 权限", "60%"}, {"", "合计", "100%"}}, []float64{1, 4, 1}, map[int]bool{1: true})
+	d.Heading(2, "登录模块")
+	d.Bullet("修复 <超时>")
+	d.Paragraph("第一行\n第二行")
+
+	var buf bytes.Buffer
+	if err := d.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	files := readZip(t, buf.Bytes())
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "word/_rels/document.xml.rels", "word/document.xml", "word/styles.xml", "word/header1.xml", "word/footer1.xml"} {
+		content, ok := files[name]
+		if !ok {
+			t.Errorf("缺少 %s", name)
+			continue
+		}
+		if err := wellFormed(content); err != nil {
+			t.Errorf("%s 不是合法的 XML: %v", name, err)
+		}
+	}
+
+	doc := files["word/document.xml"]
+	for _, want := range []string{
+		`<w:pStyle w:val="Title"/><w:jc w:val="center"/>`,
+		`<w:pStyle w:val="Heading1"/>`,
+		`<w:pStyle w:val="Heading2"/>`,
+		`<w:gridCol w:w="1504"/><w:gridCol w:w="6017"/><w:gridCol w:w="1504"/>`,
+		`<w:trPr><w:tblHeader/></w:trPr>`,
+		`登录 &amp; 权限`,
+		`<w:rPr><w:b/></w:rPr><w:t xml:space="preserve">合计</w:t>`,
+		`•</w:t><w:tab/><w:t xml:space="preserve">修复 &lt;超时&gt;`,
+		`第一行</w:t><w:br/><w:t xml:space="preserve">第二行`,
+		`</w:tbl><w:p/>`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document.xml 缺少 %s", want)
+		}
+	}
+	if !strings.Contains(files["word/header1.xml"], "张三 2025-03") {
+		t.Error("页眉缺少文字")
+	}
+	if footer := files["word/footer1.xml"]; !strings.Contains(footer, "工作总结　　第 ") || !strings.Contains(footer, " PAGE ") {
+		t.Errorf("页脚 = %s", footer)
+	}
+}
+
+func TestWriteTemplate(t *testing.T) {
+	template := buildZip(t, map[string]string{
+		"word/document.xml": `<w:document><w:body>` +
+			`<w:p><w:r><w:t>{{na</w:t></w:r><w:r><w:t>me}} 的总结</w:t></w:r></w:p>` +
+			`<w:p><w:r><w:t>{{content}}</w:t></w:r></w:p>` +
+			`<w:p><w:r><w:t>周期 {{period}}</w:t></w:r></w:p>` +
+			`<w:sectPr/></w:body></w:document>`,
+		"word/styles.xml":     `<w:styles><w:style w:type="paragraph" w:styleId="1"><w:name w:val="heading 1"/></w:style></w:styles>`,
+		"word/header1.xml":    `<w:hdr><w:p><w:r><w:t>{{name}} &amp; 团队</w:t></w:r></w:p></w:hdr>`,
+		"word/media/logo.png": "png",
+	})
+
+	d := New()
+	d.Heading(1, "一、主要工作")
+	d.Paragraph("正文")
+
+	var buf bytes.Buffer
+	vars := map[string]string{"name": "张三<测试>", "period": "2025-03"}
+	if err := d.WriteTemplate(template, vars, &buf); err != nil {
+		t.Fatal(err)
+	}
+	files := readZip(t, buf.Bytes())
+
+	doc := files["word/document.xml"]
+	for _, want := range []string{
+		`<w:t xml:space="preserve">张三&lt;测试&gt; 的总结</w:t></w:r><w:r><w:t></w:t>`,
+		`<w:pStyle w:val="1"/>`, // 标题样式映射为模板中的样式 ID
+		`正文`,
+		`周期 2025-03`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document.xml 缺少 %s:\n%s", want, doc)
+		}
+	}
+	if strings.Contains(doc, "{{") {
+		t.Errorf("document.xml 仍有占位符:\n%s", doc)
+	}
+	if header := files["word/header1.xml"]; !strings.Contains(header, "张三&lt;测试&gt; &amp; 团队") {
+		t.Errorf("页眉 = %s", header)
+	}
+	if files["word/media/logo.png"] != "png" {
+		t.Error("模板中的其他文件应原样保留")
+	}
+}
+
+// 模板没有 {{content}} 时，正文插入到节属性之前
+func TestWriteTemplateWithoutContentPlaceholder(t *testing.T) {
+	template := buildZip(t, map[string]string{
+		"word/document.xml": `<w:document><w:body><w:p><w:r><w:t>封面</w:t></w:r></w:p><w:sectPr/></w:body></w:document>`,
+	})
+
+	d := New()
+	d.Paragraph("正文")
+
+	var buf bytes.Buffer
+	if err := d.WriteTemplate(template, nil, &buf); err != nil {
+		t.Fatal(err)
+	}
+	doc := readZip(t, buf.Bytes())["word/document.xml"]
+	if cover, body, sect := strings.Index(doc, "封面"), strings.Index(doc, "正文"), strings.Index(doc, "<w:sectPr"); !(cover < body && body < sect) {
+		t.Errorf("正文位置不对:\n%s", doc)
+	}
+}
+
+// buildZip 用给定文件构造压缩包
+func buildZip(t *testing.T, files map[string]string) *zip.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	for name, content := range files {
+		fw, err := z.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(fw, content)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// readZip 读取压缩包中的所有文件
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, f := range z.File {
+		content, err := readZipFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+// wellFormed 检查 XML 是否格式正确
+func wellFormed(content string) error {
+	d := xml.NewDecoder(strings.NewReader(content))
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
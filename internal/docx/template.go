@@ -0,0 +1,162 @@
+package docx
+
+import (
+	"archive/zip"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/xmlutil"
+)
+
+// ContentPlaceholder 模板中单独占一段的正文占位符，会被替换为生成的正文
+const ContentPlaceholder = "{{content}}"
+
+var (
+	paragraphRe = regexp.MustCompile(`(?s)<w:p(?:\s[^>]*[^/>])?>.*?</w:p>`)
+	textRe      = regexp.MustCompile(`(?s)(<w:t(?: [^>]*)?>)(.*?)</w:t>`)
+	styleRe     = regexp.MustCompile(`(?s)<w:style [^>]*?w:styleId="([^"]+)"[^>]*>.*?<w:name w:val="([^"]+)"`)
+	sectPrRe    = regexp.MustCompile(`<w:sectPr[ >/]`)
+)
+
+// WriteTemplate 套用模板写出文档：
+//   - 正文、页眉、页脚中的 {{name}} 等占位符替换为 vars 中的值
+//   - 单独占一段的 {{content}} 替换为生成的正文；没有该占位符时正文追加到模板末尾
+//
+// 占位符所在段落会合并为一个文字块，段内混合的格式以第一段文字为准
+func (d *Document) WriteTemplate(template *zip.Reader, vars map[string]string, w io.Writer) error {
+	styles := templateStyles(template)
+
+	z := zip.NewWriter(w)
+	for _, f := range template.File {
+		data, err := readZipFile(f)
+		if err != nil {
+			return err
+		}
+
+		name := f.Name
+		switch {
+		case name == "word/document.xml":
+			data = []byte(d.fillDocument(string(data), vars, styles))
+		case isHeaderPart(name):
+			data = []byte(replacePlaceholders(string(data), vars))
+		}
+
+		fw, err := z.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: f.Modified})
+		if err != nil {
+			return fmt.Errorf("写入 %s 失败: %w", name, err)
+		}
+		if _, err := fw.Write(data); err != nil {
+			return fmt.Errorf("写入 %s 失败: %w", name, err)
+		}
+	}
+	return z.Close()
+}
+
+// fillDocument 替换正文占位符并插入生成的正文
+func (d *Document) fillDocument(doc string, vars map[string]string, styles map[string]string) string {
+	body := strings.Join(d.body, "")
+	for id, name := range styleNames {
+		if mapped, ok := styles[name]; ok && mapped != id {
+			body = strings.ReplaceAll(body, `<w:pStyle w:val="`+id+`"/>`, `<w:pStyle w:val="`+mapped+`"/>`)
+		}
+	}
+
+	inserted := false
+	doc = paragraphRe.ReplaceAllStringFunc(doc, func(p string) string {
+		if !inserted && strings.TrimSpace(paragraphText(p)) == ContentPlaceholder {
+			inserted = true
+			return body
+		}
+		return fillParagraph(p, vars)
+	})
+	if inserted {
+		return doc
+	}
+
+	// 没有 {{content}}：插入到最后一个 sectPr（节属性）之前
+	if locs := sectPrRe.FindAllStringIndex(doc, -1); len(locs) > 0 {
+		at := locs[len(locs)-1][0]
+		return doc[:at] + body + doc[at:]
+	}
+	return strings.Replace(doc, "</w:body>", body+"</w:body>", 1)
+}
+
+// replacePlaceholders 替换页眉页脚中每个段落的占位符
+func replacePlaceholders(xml string, vars map[string]string) string {
+	return paragraphRe.ReplaceAllStringFunc(xml, func(p string) string {
+		return fillParagraph(p, vars)
+	})
+}
+
+// fillParagraph 替换段落中的占位符；Word 常把 {{name}} 拆到多个文字块中，所以先合并再替换
+func fillParagraph(p string, vars map[string]string) string {
+	text := paragraphText(p)
+	if !strings.Contains(text, "{{") {
+		return p
+	}
+
+	for key, value := range vars {
+		text = strings.ReplaceAll(text, "{{"+key+"}}", value)
+	}
+
+	first := true
+	return textRe.ReplaceAllStringFunc(p, func(t string) string {
+		if !first {
+			return `<w:t></w:t>`
+		}
+		first = false
+		return `<w:t xml:space="preserve">` + xmlutil.Escape(text) + `</w:t>`
+	})
+}
+
+// paragraphText 返回段落中所有文字块拼接后的文本
+func paragraphText(p string) string {
+	var b strings.Builder
+	for _, m := range textRe.FindAllStringSubmatch(p, -1) {
+		b.WriteString(html.UnescapeString(m[2]))
+	}
+	return b.String()
+}
+
+// templateStyles 读取模板中的样式名到样式 ID 的映射（名称小写）
+func templateStyles(template *zip.Reader) map[string]string {
+	styles := make(map[string]string)
+	for _, f := range template.File {
+		if f.Name != "word/styles.xml" {
+			continue
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			return styles
+		}
+		for _, m := range styleRe.FindAllStringSubmatch(string(data), -1) {
+			styles[strings.ToLower(m[2])] = m[1]
+		}
+	}
+	return styles
+}
+
+// isHeaderPart 判断是否为页眉或页脚
+func isHeaderPart(name string) bool {
+	base := path.Base(name)
+	return path.Dir(name) == "word" && strings.HasSuffix(base, ".xml") &&
+		(strings.HasPrefix(base, "header") || strings.HasPrefix(base, "footer"))
+}
+
+// readZipFile 读取压缩包中的文件
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("读取模板 %s 失败: %w", f.Name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("读取模板 %s 失败: %w", f.Name, err)
+	}
+	return data, nil
+}
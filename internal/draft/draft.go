@@ -0,0 +1,215 @@
+package draft
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/paths"
+)
+
+// ConfigFileName 配置目录下的草稿配置文件
+const ConfigFileName = "draft.json"
+
+// 日报草稿的分节标题，与 KPI 站点日报格式保持一致
+const (
+	SectionDone = "今日完成工作"
+	SectionPlan = "明日工作计划"
+)
+
+// planHeadingRe 匹配日报中的明日计划标题
+var planHeadingRe = regexp.MustCompile(`(明日|明天)(工作)?计划[:：]?`)
+
+// planSplitRe 明日计划条目分隔：换行、分号或行内序号
+var planSplitRe = regexp.MustCompile(`[\n;；]+|\s+(?:\d{1,2}[.、)）])`)
+
+// itemPrefixRe 条目前的序号或列表符号
+var itemPrefixRe = regexp.MustCompile(`^\s*(?:\d{1,2}[.、)）]|[（(]\d{1,2}[)）]|[-*•·])\s*`)
+
+// Config 草稿配置
+type Config struct {
+	Repos  []string `json:"repos"`
+	Author string   `json:"author"`
+}
+
+// Commit 一次 git 提交
+type Commit struct {
+	Hash    string
+	Subject string
+	Branch  string
+	Time    time.Time
+}
+
+// RepoCommits 单个仓库当天的提交
+type RepoCommits struct {
+	Repo     string
+	Branches map[string][]Commit
+}
+
+// LoadConfig 读取草稿配置，文件不存在时返回空配置
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("读取草稿配置失败: %w", err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("解析草稿配置失败: %w", err)
+	}
+	return cfg, nil
+}
+
+// DefaultAuthor 读取全局 git 配置中的邮箱作为作者
+func DefaultAuthor() string {
+	out, err := exec.Command("git", "config", "--global", "user.email").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// ScanRepos 扫描各仓库中作者在指定日期的提交，按仓库和分支分组；
+// 无法读取的仓库跳过，错误逐个返回，不影响其他仓库
+func ScanRepos(repos []string, author string, day time.Time) ([]RepoCommits, []error) {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+
+	var result []RepoCommits
+	var errs []error
+	for _, repo := range repos {
+		repo = paths.ExpandHome(repo)
+		commits, err := repoLog(repo, author, start, end)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(commits) == 0 {
+			continue
+		}
+
+		rc := RepoCommits{Repo: repo, Branches: make(map[string][]Commit)}
+		for _, c := range commits {
+			rc.Branches[c.Branch] = append(rc.Branches[c.Branch], c)
+		}
+		result = append(result, rc)
+	}
+
+	return result, errs
+}
+
+// repoLog 获取作者时间在 [since, until) 内的提交（所有分支，不含合并提交）
+//
+// git log 的 --since/--until 按提交时间过滤，rebase、cherry-pick 后提交时间会晚于作者时间，
+// 所以只用 --since 限定下界（提交时间不早于作者时间），再在这里按作者时间筛选
+func repoLog(repo, author string, since, until time.Time) ([]Commit, error) {
+	args := []string{
+		"-C", repo, "log", "--all", "--source", "--no-merges",
+		// 多留一天，容忍不同机器之间的时钟偏差
+		"--since=" + since.AddDate(0, 0, -1).Format(time.RFC3339),
+		"--format=%H%x1f%S%x1f%aI%x1f%s",
+	}
+	if author != "" {
+		args = append(args, "--author="+author)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("读取仓库 %s 的提交失败: %v %s", repo, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var commits []Commit
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.SplitN(line, "\x1f", 4)
+		if len(parts) < 4 || seen[parts[0]] {
+			continue
+		}
+		seen[parts[0]] = true
+		t, err := time.Parse(time.RFC3339, parts[2])
+		if err != nil || t.Before(since) || !t.Before(until) {
+			continue
+		}
+		commits = append(commits, Commit{
+			Hash:    parts[0],
+			Branch:  shortRef(parts[1]),
+			Time:    t,
+			Subject: parts[3],
+		})
+	}
+
+	// git log 按时间倒序输出，日报按时间正序书写
+	sort.SliceStable(commits, func(i, j int) bool { return commits[i].Time.Before(commits[j].Time) })
+	return commits, nil
+}
+
+// ExtractPlan 从日报文本中提取明日计划条目
+func ExtractPlan(text string) []string {
+	loc := planHeadingRe.FindStringIndex(text)
+	if loc == nil {
+		return nil
+	}
+
+	var items []string
+	for _, part := range planSplitRe.Split(text[loc[1]:], -1) {
+		item := strings.TrimSpace(itemPrefixRe.ReplaceAllString(part, ""))
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Build 生成日报草稿
+func Build(repos []RepoCommits, carried []string) string {
+	var b strings.Builder
+	b.WriteString(SectionDone + "：\n")
+
+	n := 0
+	for _, rc := range repos {
+		var branches []string
+		for branch := range rc.Branches {
+			branches = append(branches, branch)
+		}
+		sort.Strings(branches)
+
+		for _, branch := range branches {
+			var subjects []string
+			for _, c := range rc.Branches[branch] {
+				subjects = append(subjects, c.Subject)
+			}
+			n++
+			fmt.Fprintf(&b, "%d. 【%s/%s】%s\n", n, filepath.Base(rc.Repo), branch, strings.Join(subjects, "；"))
+		}
+	}
+	for _, item := range carried {
+		n++
+		fmt.Fprintf(&b, "%d. %s（昨日计划）\n", n, item)
+	}
+	if n == 0 {
+		b.WriteString("1. \n")
+	}
+
+	b.WriteString("\n" + SectionPlan + "：\n1. \n")
+	return b.String()
+}
+
+// shortRef 去掉 refs/heads/ 等前缀
+func shortRef(ref string) string {
+	for _, prefix := range []string{"refs/heads/", "refs/remotes/", "refs/tags/"} {
+		ref = strings.TrimPrefix(ref, prefix)
+	}
+	return ref
+}
@@ -0,0 +1,80 @@
+package draft
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gitRepo 创建临时仓库并按给定的作者时间和提交时间提交
+func gitRepo(t *testing.T, commits [][3]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("没有 git")
+	}
+	dir := t.TempDir()
+	git := func(env []string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git(nil, "init", "-q", "-b", "main")
+	for _, c := range commits {
+		subject, authorDate, commitDate := c[0], c[1], c[2]
+		git([]string{
+			"GIT_AUTHOR_NAME=dev", "GIT_AUTHOR_EMAIL=dev@example.com", "GIT_AUTHOR_DATE=" + authorDate,
+			"GIT_COMMITTER_NAME=dev", "GIT_COMMITTER_EMAIL=dev@example.com", "GIT_COMMITTER_DATE=" + commitDate,
+		}, "commit", "-q", "--allow-empty", "-m", subject)
+	}
+	return dir
+}
+
+// 按作者时间筛选：rebase 后提交时间变晚的提交仍算作原来那天的工作
+func TestScanReposByAuthorDate(t *testing.T) {
+	repo := gitRepo(t, [][3]string{
+		{"前一天的提交", "2025-03-09T18:00:00+08:00", "2025-03-09T18:00:00+08:00"},
+		{"当天的提交", "2025-03-10T10:00:00+08:00", "2025-03-10T10:00:00+08:00"},
+		{"当天写、次日 rebase", "2025-03-10T16:00:00+08:00", "2025-03-11T09:00:00+08:00"},
+		{"前一天写、当天 rebase", "2025-03-09T20:00:00+08:00", "2025-03-10T11:00:00+08:00"},
+		{"次日的提交", "2025-03-11T10:00:00+08:00", "2025-03-11T10:00:00+08:00"},
+	})
+
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	result, errs := ScanRepos([]string{repo}, "dev@example.com", day)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if len(result) != 1 {
+		t.Fatalf("仓库数 = %d，期望 1", len(result))
+	}
+
+	var subjects []string
+	for _, c := range result[0].Branches["main"] {
+		subjects = append(subjects, c.Subject)
+	}
+	if got, want := strings.Join(subjects, "|"), "当天的提交|当天写、次日 rebase"; got != want {
+		t.Errorf("提交 = %s，期望 %s", got, want)
+	}
+}
+
+// 无法读取的仓库跳过并返回错误，不影响其他仓库
+func TestScanReposSkipsUnreadableRepo(t *testing.T) {
+	repo := gitRepo(t, [][3]string{
+		{"当天的提交", "2025-03-10T10:00:00+08:00", "2025-03-10T10:00:00+08:00"},
+	})
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	result, errs := ScanRepos([]string{missing, repo}, "", day)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), missing) {
+		t.Errorf("errs = %v，期望只有 %s 的错误", errs, missing)
+	}
+	if len(result) != 1 || result[0].Repo != repo {
+		t.Errorf("result = %+v，期望包含可读的仓库", result)
+	}
+}
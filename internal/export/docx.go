@@ -0,0 +1,109 @@
+package export
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/calendar"
+	"github.com/Xuzan9396/yst_go_mcp/internal/docx"
+)
+
+// DocxTemplateFileName 配置目录下的默认 Word 模板
+const DocxTemplateFileName = "summary_template.docx"
+
+// DocxOptions Word 导出选项
+type DocxOptions struct {
+	Name     string      // 姓名，显示在副标题、页眉和页脚
+	Template *zip.Reader // 模板，为空时使用内置样式
+}
+
+// Title 返回文档标题，例如 2025年3月工作总结、2025-01 ~ 2025-03 工作总结
+func (s *Summary) Title() string {
+	switch len(s.Months) {
+	case 0:
+		return s.Period + " 工作总结"
+	case 1:
+		if t, err := time.Parse(calendar.MonthLayout, s.Months[0].Month); err == nil {
+			return fmt.Sprintf("%d年%d月工作总结", t.Year(), t.Month())
+		}
+		return s.Months[0].Month + " 工作总结"
+	}
+	return fmt.Sprintf("%s ~ %s 工作总结", s.Months[0].Month, s.Months[len(s.Months)-1].Month)
+}
+
+// DocxVars 返回模板占位符的值：{{title}} {{name}} {{period}} {{label}} {{date}}
+func (s *Summary) DocxVars(name string) map[string]string {
+	return map[string]string{
+		"title":  s.Title(),
+		"name":   name,
+		"period": s.Period,
+		"label":  s.Label,
+		"date":   time.Now().Format(calendar.DateLayout),
+	}
+}
+
+// WriteDOCX 写出 Word 工作总结：标题、每月汇总表，以及每个任务的工作说明
+func WriteDOCX(s *Summary, opts DocxOptions, w io.Writer) error {
+	doc := docx.New()
+	doc.Header = s.Title()
+	doc.Footer = s.Period
+	if opts.Name != "" {
+		doc.Header = opts.Name + "　" + s.Title()
+		doc.Footer = opts.Name + "　" + s.Period
+	}
+
+	// 使用模板时标题、页眉页脚由模板决定
+	if opts.Template == nil {
+		doc.Title(s.Title())
+		subtitle := "周期：" + s.Period
+		if opts.Name != "" {
+			subtitle = "姓名：" + opts.Name + "　　" + subtitle
+		}
+		doc.Centered(subtitle)
+	}
+
+	for _, ms := range s.Months {
+		prefix := s.sectionPrefix(ms)
+		doc.Heading(1, prefix+"工作汇总")
+
+		var rows [][]string
+		for i, t := range ms.Tasks {
+			rows = append(rows, []string{strconv.Itoa(i + 1), t.Name, strconv.Itoa(t.Weight) + "%", t.Outcome()})
+		}
+		rows = append(rows, []string{"", "合计", "100%", ""})
+		doc.Table([]string{"序号", "主要工作任务", "权重", "任务成果情况"}, rows,
+			[]float64{8, 22, 10, 60}, map[int]bool{len(rows) - 1: true})
+
+		doc.Heading(1, prefix+"工作说明")
+		for i, t := range ms.Tasks {
+			doc.Heading(2, fmt.Sprintf("%d. %s（权重 %d%%）", i+1, t.Name, t.Weight))
+			doc.Paragraph(taskNarrative(t, s.Label))
+			for _, entry := range t.Entries {
+				doc.Bullet(entry)
+			}
+		}
+	}
+
+	if len(s.Months) == 0 {
+		doc.Paragraph(fmt.Sprintf("%s 没有%s。", s.Period, s.Label))
+	}
+
+	if opts.Template != nil {
+		return doc.WriteTemplate(opts.Template, s.DocxVars(opts.Name), w)
+	}
+	return doc.Write(w)
+}
+
+// taskNarrative 任务的概述：涉及天数和时间跨度
+func taskNarrative(t Task, label string) string {
+	switch {
+	case t.FirstSeen == "":
+		return fmt.Sprintf("共在 %d 条%s条目中提及，主要工作如下：", len(t.Entries), label)
+	case t.FirstSeen == t.LastSeen:
+		return fmt.Sprintf("%s 完成，主要工作如下：", t.FirstSeen)
+	}
+	return fmt.Sprintf("%s 至 %s 期间持续推进，涉及 %d 天，主要工作如下：", t.FirstSeen, t.LastSeen, t.Days)
+}
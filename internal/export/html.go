@@ -0,0 +1,194 @@
+package export
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/collector"
+)
+
+// HTMLOptions HTML 导出选项
+type HTMLOptions struct {
+	Name        string // 姓名，显示在副标题
+	AssetBase   string // HTML 文件所在目录，附件本地副本的链接相对该目录
+	EmbedImages bool   // 图片以 data: 地址内嵌，生成不依赖外部文件的页面（打印 PDF 时使用）
+}
+
+// htmlTask 模板中的任务
+type htmlTask struct {
+	Task
+	Index     int
+	Narrative string
+}
+
+// htmlMonth 模板中的一个月
+type htmlMonth struct {
+	Prefix string
+	Tasks  []htmlTask
+}
+
+// htmlReport 模板中的一份报告
+type htmlReport struct {
+	Date        string
+	Text        string
+	Link        string
+	Attachments []htmlAttachment
+}
+
+// htmlAttachment 模板中的附件：Href 为本地相对路径或原始地址，由模板按普通链接过滤；
+// Embedded 只保存由本地图片生成的 data: 地址
+type htmlAttachment struct {
+	Name     string
+	Href     string
+	Embedded template.URL
+	Image    bool
+}
+
+// WriteHTML 写出 HTML 工作总结：汇总表、工作说明、提交统计和报告明细，样式内嵌，可直接打印
+func WriteHTML(s *Summary, opts HTMLOptions, w io.Writer) error {
+	data := struct {
+		*Summary
+		Title       string
+		Name        string
+		MonthViews  []htmlMonth
+		ReportViews []htmlReport
+	}{Summary: s, Title: s.Title(), Name: opts.Name}
+
+	for _, ms := range s.Months {
+		view := htmlMonth{Prefix: s.sectionPrefix(ms)}
+		for i, t := range ms.Tasks {
+			view.Tasks = append(view.Tasks, htmlTask{Task: t, Index: i + 1, Narrative: taskNarrative(t, s.Label)})
+		}
+		data.MonthViews = append(data.MonthViews, view)
+	}
+	for _, r := range s.Reports {
+		data.ReportViews = append(data.ReportViews, htmlReport{
+			Date:        reportDate(r),
+			Text:        r.Text,
+			Link:        r.Link,
+			Attachments: htmlAttachments(r.Attachments, opts),
+		})
+	}
+
+	if err := htmlTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("生成 HTML 失败: %w", err)
+	}
+	return nil
+}
+
+// htmlAttachments 计算附件链接：优先本地副本，没有时使用原始地址。
+// MD 文件中的地址不可信，不绕过模板的 URL 过滤；未下载到本地的 data: 地址直接跳过
+func htmlAttachments(attachments []collector.Attachment, opts HTMLOptions) []htmlAttachment {
+	var views []htmlAttachment
+	for _, a := range attachments {
+		view := htmlAttachment{Name: a.Name, Href: a.URL, Image: a.Image}
+		if a.Local != "" {
+			view.Href = filepath.ToSlash(a.Local)
+			if opts.AssetBase != "" && filepath.IsAbs(a.Local) {
+				if rel, err := filepath.Rel(opts.AssetBase, a.Local); err == nil {
+					view.Href = filepath.ToSlash(rel)
+				}
+			}
+			if opts.EmbedImages && a.Image && strings.HasPrefix(mimeType(a.Local), "image/") {
+				if data, err := os.ReadFile(a.Local); err == nil {
+					view.Embedded = template.URL("data:" + mimeType(a.Local) + ";base64," + base64.StdEncoding.EncodeToString(data))
+				}
+			}
+		}
+		if view.Embedded == "" && (view.Href == "" || strings.HasPrefix(strings.ToLower(strings.TrimSpace(view.Href)), "data:")) {
+			continue
+		}
+		if view.Name == "" {
+			view.Name = path.Base(view.Href)
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+// mimeType 按扩展名返回图片类型
+func mimeType(file string) string {
+	if t := mime.TypeByExtension(strings.ToLower(filepath.Ext(file))); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+var htmlTemplate = template.Must(template.New("summary").Funcs(template.FuncMap{
+	"percent": func(f float64) string { return strconv.FormatFloat(f, 'f', 1, 64) + "%" },
+	"number":  func(f float64) string { return strconv.FormatFloat(f, 'f', 1, 64) },
+}).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: "PingFang SC", "Microsoft YaHei", "Noto Sans CJK SC", "Source Han Sans SC", sans-serif; font-size: 14px; line-height: 1.6; color: #222; max-width: 960px; margin: 0 auto; padding: 24px; }
+h1 { text-align: center; font-size: 24px; margin: 8px 0; }
+.subtitle { text-align: center; color: #555; margin-bottom: 24px; }
+h2 { font-size: 18px; border-bottom: 2px solid #4472c4; padding-bottom: 4px; margin-top: 28px; page-break-after: avoid; }
+h3 { font-size: 15px; margin: 16px 0 4px; page-break-after: avoid; }
+table { width: 100%; border-collapse: collapse; margin: 8px 0 16px; }
+th, td { border: 1px solid #999; padding: 6px 8px; vertical-align: top; }
+th { background: #d9e1f2; text-align: center; }
+tr { page-break-inside: avoid; }
+td.center { text-align: center; white-space: nowrap; }
+tr.total td { font-weight: bold; }
+.pre { white-space: pre-wrap; word-break: break-word; }
+.report { border-bottom: 1px solid #ddd; padding: 8px 0; page-break-inside: avoid; }
+.report .date { font-weight: bold; }
+.report a { margin-left: 8px; font-size: 12px; }
+.attachments { margin-top: 6px; }
+.attachments figure { margin: 8px 0; page-break-inside: avoid; }
+.attachments img { max-width: 100%; border: 1px solid #ddd; }
+.attachments figcaption { font-size: 12px; color: #666; }
+a { color: #0563c1; }
+@media print { body { max-width: none; padding: 0; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="subtitle">{{if .Name}}姓名：{{.Name}}　　{{end}}周期：{{.Period}}</p>
+{{range .MonthViews}}
+<h2>{{.Prefix}}工作汇总</h2>
+<table>
+<thead><tr><th style="width:8%">序号</th><th style="width:22%">主要工作任务</th><th style="width:10%">权重</th><th>任务成果情况</th></tr></thead>
+<tbody>
+{{range .Tasks}}<tr><td class="center">{{.Index}}</td><td>{{.Name}}</td><td class="center">{{.Weight}}%</td><td class="pre">{{.Outcome}}</td></tr>
+{{end}}<tr class="total"><td></td><td>合计</td><td class="center">100%</td><td></td></tr>
+</tbody>
+</table>
+<h2>{{.Prefix}}工作说明</h2>
+{{range .Tasks}}<h3>{{.Index}}. {{.Name}}（权重 {{.Weight}}%）</h3>
+<p>{{.Narrative}}</p>
+<ul>{{range .Entries}}<li class="pre">{{.}}</li>{{end}}</ul>
+{{end}}{{end}}
+{{with .Stats}}
+<h2>提交统计</h2>
+<table>
+<thead><tr><th>月份</th><th>{{$.Label}}数</th><th>工作日</th><th>已提交天数</th><th>提交率</th><th>平均字数</th><th>工时</th></tr></thead>
+<tbody>
+{{range .Months}}<tr><td class="center">{{.Month}}</td><td class="center">{{.Reports}}</td><td class="center">{{.Workdays}}</td><td class="center">{{.ReportedDays}}</td><td class="center">{{percent .SubmissionRate}}</td><td class="center">{{number .AvgLength}}</td><td class="center">{{number .TotalHours}}</td></tr>
+{{end}}<tr class="total"><td class="center">合计</td><td class="center">{{.TotalReports}}</td><td class="center">{{.Workdays}}</td><td class="center">{{.ReportedDays}}</td><td class="center">{{percent .SubmissionRate}}</td><td class="center">{{number .AvgLength}}</td><td class="center">{{number .TotalHours}}</td></tr>
+</tbody>
+</table>
+{{if .LongestStreak.Days}}<p>最长连续提交：{{.LongestStreak.Days}} 个工作日（{{.LongestStreak.Start}} ~ {{.LongestStreak.End}}）</p>{{end}}
+{{end}}
+<h2>{{.Label}}明细</h2>
+{{range .ReportViews}}<div class="report"><span class="date">{{.Date}}</span>{{if .Link}}<a href="{{.Link}}">查看原文</a>{{end}}
+<div class="pre">{{.Text}}</div>
+{{if .Attachments}}<div class="attachments">{{range .Attachments}}{{if .Image}}<figure><img src="{{if .Embedded}}{{.Embedded}}{{else}}{{.Href}}{{end}}" alt="{{.Name}}">{{if .Name}}<figcaption>{{.Name}}</figcaption>{{end}}</figure>{{else}}<div>📎 <a href="{{.Href}}">{{.Name}}</a></div>{{end}}{{end}}</div>{{end}}
+</div>
+{{else}}<p>没有{{.Label}}。</p>
+{{end}}
+</body>
+</html>
+`))
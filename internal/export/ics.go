@@ -0,0 +1,172 @@
+package export
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/calendar"
+	"github.com/Xuzan9396/yst_go_mcp/internal/collector"
+	"github.com/Xuzan9396/yst_go_mcp/internal/draft"
+)
+
+// iCalendar 格式参数（RFC 5545）
+const (
+	icsProdID     = "-//Xuzan9396//yst_go_mcp//CN"
+	icsDateLayout = "20060102"
+	icsStampFmt   = "20060102T150405Z"
+	icsLineOctets = 75
+	icsUIDDomain  = "yst-go-mcp"
+	// PlanCategory 明日计划事件的分类
+	PlanCategory = "明日计划"
+)
+
+// ICSOptions 日历导出选项
+type ICSOptions struct {
+	Name     string             // 日历名称，为空时使用 <标签>（<周期>）
+	Plans    bool               // 是否为明日计划条目生成事件
+	Calendar *calendar.Calendar // 明日计划放在报告之后的下一个工作日；为 nil 时只跳过周末
+}
+
+// Event 日历中的一个全天事件
+type Event struct {
+	UID         string
+	Date        time.Time
+	Summary     string
+	Description string
+	URL         string
+	Category    string
+	Plan        bool // 是否为明日计划事件
+}
+
+// ReportEvents 为每份有日期的报告生成一个全天事件（标题为报告第一行，描述为正文，URL 为原文链接），
+// 开启 Plans 时为每条明日计划在下一个工作日生成事件
+func ReportEvents(s *Summary, opts ICSOptions) []Event {
+	cal := opts.Calendar
+	if cal == nil {
+		cal = calendar.New()
+	}
+
+	var events []Event
+	for _, r := range s.Reports {
+		if r.Date.IsZero() {
+			continue
+		}
+		title, body, _ := strings.Cut(strings.TrimSpace(r.Text), "\n")
+		link := ""
+		if r.Link != "" {
+			link, _ = collector.ResolveLink(r.Link)
+		}
+		date := reportDate(r)
+		key := link
+		if key == "" {
+			key = r.Text
+		}
+		events = append(events, Event{
+			UID:         eventUID("report", date, key),
+			Date:        r.Date,
+			Summary:     strings.TrimSpace(title),
+			Description: strings.TrimSpace(body),
+			URL:         link,
+			Category:    s.Label,
+		})
+
+		if !opts.Plans {
+			continue
+		}
+		next := cal.NextWorkday(r.Date)
+		for i, item := range draft.ExtractPlan(r.Text) {
+			events = append(events, Event{
+				UID:         eventUID("plan", date, fmt.Sprintf("%d-%s", i, item)),
+				Date:        next,
+				Summary:     PlanCategory + "：" + item,
+				Description: fmt.Sprintf("来自 %s %s", date, s.Label),
+				URL:         link,
+				Category:    PlanCategory,
+				Plan:        true,
+			})
+		}
+	}
+	return events
+}
+
+// eventUID 按报告日期和内容生成稳定的 UID，重复导入同一份日历时更新而不是新增事件
+func eventUID(kind, date, key string) string {
+	sum := sha1.Sum([]byte(key))
+	return fmt.Sprintf("%s-%s-%s@%s", kind, strings.ReplaceAll(date, "-", ""), hex.EncodeToString(sum[:6]), icsUIDDomain)
+}
+
+// WriteICS 写出 iCalendar 文件，可导入系统日历、Outlook、Google 日历等
+func WriteICS(s *Summary, opts ICSOptions, events []Event, w io.Writer) error {
+	name := opts.Name
+	if name == "" {
+		name = fmt.Sprintf("%s（%s）", s.Label, s.Period)
+	}
+	stamp := time.Now().UTC().Format(icsStampFmt)
+
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeICSLine(bw, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", icsProdID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", icsText(name))
+	for _, e := range events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", stamp)
+		line("DTSTART;VALUE=DATE", e.Date.Format(icsDateLayout))
+		line("DTEND;VALUE=DATE", e.Date.AddDate(0, 0, 1).Format(icsDateLayout))
+		line("SUMMARY", icsText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", icsText(e.Description))
+		}
+		if e.URL != "" {
+			line("URL", e.URL)
+		}
+		if e.Category != "" {
+			line("CATEGORIES", icsText(e.Category))
+		}
+		// 全天事件不占用忙碌时间
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("写入日历失败: %w", err)
+	}
+	return nil
+}
+
+// icsText 转义 TEXT 类型的值：反斜杠、分号、逗号和换行
+func icsText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", "").Replace(s)
+}
+
+// writeICSLine 写出一行内容，超过 75 字节时折行（续行以空格开头），不拆开 UTF-8 字符，行尾为 CRLF
+func writeICSLine(w *bufio.Writer, s string) {
+	limit := icsLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// 续行开头的空格占一个字节
+		limit = icsLineOctets - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,177 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/calendar"
+	"github.com/Xuzan9396/yst_go_mcp/internal/collector"
+)
+
+func TestWriteICSLine(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"短行不折", "SUMMARY:日报", "SUMMARY:日报\r\n"},
+		{"恰好 75 字节", strings.Repeat("a", 75), strings.Repeat("a", 75) + "\r\n"},
+		{"76 字节", strings.Repeat("a", 76), strings.Repeat("a", 75) + "\r\n a\r\n"},
+		{
+			"续行算上开头空格也不超过 75 字节",
+			strings.Repeat("a", 75+74+1),
+			strings.Repeat("a", 75) + "\r\n " + strings.Repeat("a", 74) + "\r\n a\r\n",
+		},
+		{
+			"不拆开中文字符",
+			"SUMMARY:" + strings.Repeat("中", 25), // 8 + 75 字节，第 75 字节落在第 23 个汉字中间
+			"SUMMARY:" + strings.Repeat("中", 22) + "\r\n " + strings.Repeat("中", 3) + "\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := bufio.NewWriter(&buf)
+			writeICSLine(w, tt.in)
+			w.Flush()
+			if got := buf.String(); got != tt.want {
+				t.Errorf("writeICSLine()\n得到：%q\n期望：%q", got, tt.want)
+			}
+			assertFolded(t, buf.String())
+
+			// 展开折行后应与原文一致
+			if unfolded := strings.TrimSuffix(strings.ReplaceAll(buf.String(), "\r\n ", ""), "\r\n"); unfolded != tt.in {
+				t.Errorf("展开后 = %q，期望 %q", unfolded, tt.in)
+			}
+		})
+	}
+}
+
+func TestICSText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"修复登录", "修复登录"},
+		{"a,b;c", `a\,b\;c`},
+		{`C:\path`, `C:\\path`},
+		{"第一行\r\n第二行\n第三行", `第一行\n第二行\n第三行`},
+	}
+	for _, tt := range tests {
+		if got := icsText(tt.in); got != tt.want {
+			t.Errorf("icsText(%q) = %q，期望 %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestReportEvents(t *testing.T) {
+	s := &Summary{
+		Label:  "日报",
+		Period: "2025-03-01 ~ 2025-03-31",
+		Reports: []collector.Report{
+			{
+				Text: "2025-03-14 日报\n今日完成：修复登录\n明日计划：1. 联调接口 2. 写文档",
+				Link: "/report/report-daily/view?id=1",
+				Date: time.Date(2025, 3, 14, 0, 0, 0, 0, time.Local), // 周五
+			},
+			{Text: "没有日期的报告"},
+		},
+	}
+
+	cal := calendar.New()
+	events := ReportEvents(s, ICSOptions{Plans: true, Calendar: cal})
+	if len(events) != 3 {
+		t.Fatalf("事件数 = %d，期望 3（1 份报告 + 2 条计划）: %+v", len(events), events)
+	}
+
+	report := events[0]
+	if report.Summary != "2025-03-14 日报" || report.Description != "今日完成：修复登录\n明日计划：1. 联调接口 2. 写文档" {
+		t.Errorf("报告事件 = %+v", report)
+	}
+	if want := collector.BaseURL + "/report/report-daily/view?id=1"; report.URL != want {
+		t.Errorf("URL = %s，期望 %s", report.URL, want)
+	}
+
+	for i, want := range []string{"明日计划：联调接口", "明日计划：写文档"} {
+		plan := events[i+1]
+		if plan.Summary != want || !plan.Plan || plan.Category != PlanCategory {
+			t.Errorf("计划事件 %d = %+v", i, plan)
+		}
+		// 周五的计划放到下周一
+		if got := plan.Date.Format(calendar.DateLayout); got != "2025-03-17" {
+			t.Errorf("计划日期 = %s，期望 2025-03-17", got)
+		}
+	}
+
+	// UID 稳定且互不相同
+	again := ReportEvents(s, ICSOptions{Plans: true, Calendar: cal})
+	seen := make(map[string]bool)
+	for i, e := range events {
+		if e.UID != again[i].UID {
+			t.Errorf("UID 不稳定: %s != %s", e.UID, again[i].UID)
+		}
+		if seen[e.UID] {
+			t.Errorf("UID 重复: %s", e.UID)
+		}
+		seen[e.UID] = true
+	}
+
+	if events := ReportEvents(s, ICSOptions{}); len(events) != 1 {
+		t.Errorf("不开启 Plans 时事件数 = %d，期望 1", len(events))
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	s := testSummary()
+	events := ReportEvents(s, ICSOptions{})
+	events[0].Description = strings.Repeat("很长的描述，", 30)
+
+	var buf bytes.Buffer
+	if err := WriteICS(s, ICSOptions{Name: "张三的日报"}, events, &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	assertFolded(t, out)
+
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:张三的日报\r\n",
+		"DTSTART;VALUE=DATE:20250310\r\nDTEND;VALUE=DATE:20250311\r\n",
+		"SUMMARY:2025-03-10 日报 修复登录\r\n",
+		"DESCRIPTION:" + strings.Repeat("很长的描述，", 30) + "\r\n",
+		"URL:https://kpi.example.com/view?id=1\r\n",
+		"CATEGORIES:日报\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("日历缺少 %q", want)
+		}
+	}
+	if strings.Count(out, "BEGIN:VEVENT") != 2 || strings.Count(out, "END:VEVENT") != 2 {
+		t.Errorf("事件数不对:\n%s", out)
+	}
+}
+
+// assertFolded 检查每行不超过 75 字节、以 CRLF 结尾且是完整的 UTF-8
+func assertFolded(t *testing.T, out string) {
+	t.Helper()
+	if !strings.HasSuffix(out, "\r\n") {
+		t.Errorf("没有以 CRLF 结尾: %q", out)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > icsLineOctets {
+			t.Errorf("行长 %d 字节，超过 %d: %q", len(line), icsLineOctets, line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("行中有被拆开的 UTF-8 字符: %q", line)
+		}
+		if strings.Contains(line, "\n") {
+			t.Errorf("行中有裸换行: %q", line)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/analysis"
+	"github.com/Xuzan9396/yst_go_mcp/internal/calendar"
+	"github.com/Xuzan9396/yst_go_mcp/internal/collector"
+)
+
+// outcomeEntries 任务成果情况最多列出的日报条目数
+const outcomeEntries = 3
+
+// Summary 导出文件使用的汇总数据：按月的任务汇总、全部报告和统计
+type Summary struct {
+	Label   string // 日报 / 周报 / 月报
+	Period  string // 导出范围，例如 2025-03-01 ~ 2025-03-31
+	Months  []MonthSummary
+	Reports []collector.Report // 全部报告，按日期排序
+	Stats   *analysis.Stats    // 提交统计，只有日报有
+}
+
+// MonthSummary 一个月的任务汇总
+type MonthSummary struct {
+	Month   string
+	Reports []collector.Report
+	Tasks   []Task
+}
+
+// Task 汇总表中的一行
+type Task struct {
+	Name      string
+	Weight    int // 百分比，同月所有任务之和为 100
+	Days      int
+	FirstSeen string
+	LastSeen  string
+	Entries   []string // 相关的日报条目
+}
+
+// Outcome 返回任务成果情况文本：前几条相关条目，每条一行
+func (t Task) Outcome() string {
+	return strings.Join(t.Entries[:min(len(t.Entries), outcomeEntries)], "\n")
+}
+
+// NewSummary 按月提取任务并计算权重，taskLimit 为每月最多任务数
+func NewSummary(label, period string, months []string, byMonth map[string][]collector.Report, extractor *analysis.Extractor, taskLimit int) *Summary {
+	s := &Summary{Label: label, Period: period}
+	for _, month := range months {
+		reports := byMonth[month]
+		s.Reports = append(s.Reports, reports...)
+		if len(reports) == 0 {
+			continue
+		}
+
+		ms := MonthSummary{Month: month, Reports: reports}
+		extraction := extractor.Extract(reports, taskLimit)
+		weights := analysis.TaskWeights(extraction.Tasks)
+		for i, t := range extraction.Tasks {
+			ms.Tasks = append(ms.Tasks, Task{
+				Name:      t.Name,
+				Weight:    weights[i],
+				Days:      t.Days,
+				FirstSeen: t.FirstSeen,
+				LastSeen:  t.LastSeen,
+				Entries:   t.Entries,
+			})
+		}
+		s.Months = append(s.Months, ms)
+	}
+
+	sort.SliceStable(s.Reports, func(i, j int) bool {
+		return s.Reports[i].Date.Before(s.Reports[j].Date)
+	})
+	return s
+}
+
+// sectionPrefix 多个月时章节标题带月份前缀，例如 "2025-03 "
+func (s *Summary) sectionPrefix(ms MonthSummary) string {
+	if len(s.Months) == 1 {
+		return ""
+	}
+	return ms.Month + " "
+}
+
+// GroupByMonth 按日报日期把报告分到各月，没有日期的报告丢弃，返回排好序的月份
+func GroupByMonth(reports []collector.Report) ([]string, map[string][]collector.Report) {
+	byMonth := make(map[string][]collector.Report)
+	for _, r := range reports {
+		if r.Date.IsZero() {
+			continue
+		}
+		month := r.Date.Format(calendar.MonthLayout)
+		byMonth[month] = append(byMonth[month], r)
+	}
+
+	months := make([]string, 0, len(byMonth))
+	for month := range byMonth {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+	return months, byMonth
+}
+
+// reportDate 返回报告日期，没有日期时为空
+func reportDate(r collector.Report) string {
+	if r.Date.IsZero() {
+		return ""
+	}
+	return r.Date.Format(calendar.DateLayout)
+}
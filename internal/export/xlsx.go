@@ -0,0 +1,171 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/xlsx"
+)
+
+// WriteXLSX 写出 Excel 工作簿：每月一张汇总表、一张报告明细表，日报额外有一张统计表
+func WriteXLSX(s *Summary, w io.Writer) error {
+	wb := xlsx.New()
+	for _, ms := range s.Months {
+		addSummarySheet(wb, ms)
+	}
+	addReportsSheet(wb, s)
+	if s.Stats != nil {
+		addStatsSheet(wb, s)
+	}
+	return wb.Write(w)
+}
+
+// addSummarySheet 月度汇总表：序号/主要工作任务/权重/任务成果情况
+//
+// 权重与 CSV、Word、HTML 导出一致，为按涉及天数分配的整数百分比（最大余数法，合计 100%）；
+// 权重单元格是普通数值，可直接调整，合计行用公式求和
+func addSummarySheet(wb *xlsx.Workbook, ms MonthSummary) {
+	sheet := wb.AddSheet(ms.Month + " 汇总")
+	sheet.SetColWidth(0, 6)
+	sheet.SetColWidth(1, 24)
+	sheet.SetColWidth(2, 8)
+	sheet.SetColWidth(3, 60)
+	sheet.FreezeRows(1)
+
+	sheet.AddRow(
+		headerCell("序号"),
+		headerCell("主要工作任务"),
+		headerCell("权重"),
+		headerCell("任务成果情况"),
+	)
+
+	totalWeight := 0
+	for i, t := range ms.Tasks {
+		totalWeight += t.Weight
+		sheet.AddRow(
+			xlsx.Number(float64(i+1)),
+			xlsx.Text(t.Name),
+			xlsx.Number(float64(t.Weight)/100).WithStyle(xlsx.StylePercent),
+			xlsx.Text(t.Outcome()),
+		)
+	}
+
+	if len(ms.Tasks) > 0 {
+		sheet.AddRow(
+			xlsx.Empty(),
+			xlsx.Text("合计").WithStyle(xlsx.StyleBold),
+			xlsx.Formula(fmt.Sprintf("SUM(C2:C%d)", len(ms.Tasks)+1), float64(totalWeight)/100).WithStyle(xlsx.StyleBoldPercent),
+			xlsx.Empty(),
+		)
+	}
+}
+
+// addReportsSheet 报告明细表，链接列可直接点击打开原文
+func addReportsSheet(wb *xlsx.Workbook, s *Summary) {
+	sheet := wb.AddSheet(s.Label + "明细")
+	sheet.SetColWidth(0, 12)
+	sheet.SetColWidth(1, 18)
+	sheet.SetColWidth(2, 80)
+	sheet.SetColWidth(3, 12)
+	sheet.FreezeRows(1)
+
+	sheet.AddRow(
+		headerCell("日期"),
+		headerCell("提交时间"),
+		headerCell("内容"),
+		headerCell("链接"),
+	)
+
+	for _, r := range s.Reports {
+		submitted := ""
+		if !r.SubmittedAt.IsZero() {
+			submitted = r.SubmittedAt.Format("2006-01-02 15:04")
+		}
+		link := xlsx.Empty()
+		if r.Link != "" {
+			link = xlsx.Link("查看原文", r.Link)
+		}
+		sheet.AddRow(
+			xlsx.Text(reportDate(r)),
+			xlsx.Text(submitted),
+			xlsx.Text(r.Text),
+			link,
+		)
+	}
+}
+
+// addStatsSheet 统计表：按月的提交情况，提交率和合计行使用公式
+func addStatsSheet(wb *xlsx.Workbook, s *Summary) {
+	sheet := wb.AddSheet("统计")
+	for col, width := range []float64{12, 10, 10, 12, 10, 10, 10} {
+		sheet.SetColWidth(col, width)
+	}
+	sheet.FreezeRows(1)
+
+	sheet.AddRow(
+		headerCell("月份"),
+		headerCell(s.Label+"数"),
+		headerCell("工作日"),
+		headerCell("已提交天数"),
+		headerCell("提交率"),
+		headerCell("平均字数"),
+		headerCell("工时"),
+	)
+
+	stats := s.Stats
+	for i, m := range stats.Months {
+		row := i + 2
+		sheet.AddRow(
+			xlsx.Text(m.Month),
+			xlsx.Number(float64(m.Reports)),
+			xlsx.Number(float64(m.Workdays)),
+			xlsx.Number(float64(m.ReportedDays)),
+			xlsx.Formula(fmt.Sprintf("IF(C%d=0,0,D%d/C%d)", row, row, row), m.SubmissionRate/100).WithStyle(xlsx.StylePercent1),
+			xlsx.Number(round1(m.AvgLength)),
+			xlsx.Number(round1(m.TotalHours)),
+		)
+	}
+
+	if n := len(stats.Months); n > 0 {
+		first, last, row := 2, n+1, n+2
+		sum := func(col string, cached float64) xlsx.Cell {
+			return xlsx.Formula(fmt.Sprintf("SUM(%s%d:%s%d)", col, first, col, last), cached).WithStyle(xlsx.StyleBold)
+		}
+		sheet.AddRow(
+			xlsx.Text("合计").WithStyle(xlsx.StyleBold),
+			sum("B", float64(stats.TotalReports)),
+			sum("C", float64(stats.Workdays)),
+			sum("D", float64(stats.ReportedDays)),
+			xlsx.Formula(fmt.Sprintf("IF(C%d=0,0,D%d/C%d)", row, row, row), stats.SubmissionRate/100).WithStyle(xlsx.StylePercent1),
+			xlsx.Number(round1(stats.AvgLength)).WithStyle(xlsx.StyleBold),
+			sum("G", round1(stats.TotalHours)),
+		)
+	}
+
+	sheet.AddRow()
+	if stats.AvgHours > 0 {
+		sheet.AddRow(xlsx.Text("平均工时"), xlsx.Text(fmt.Sprintf("%.1f 小时/份", stats.AvgHours)))
+	}
+	if len(stats.BusiestDays) > 0 {
+		var parts []string
+		for _, d := range stats.BusiestDays {
+			parts = append(parts, fmt.Sprintf("%s %d", d.Weekday, d.Reports))
+		}
+		sheet.AddRow(xlsx.Text("按星期分布"), xlsx.Text(strings.Join(parts, "、")))
+	}
+	if stats.LongestStreak.Days > 0 {
+		sheet.AddRow(xlsx.Text("最长连续提交"), xlsx.Text(fmt.Sprintf("%d 个工作日（%s ~ %s）",
+			stats.LongestStreak.Days, stats.LongestStreak.Start, stats.LongestStreak.End)))
+	}
+}
+
+// headerCell 表头单元格
+func headerCell(text string) xlsx.Cell {
+	return xlsx.Text(text).WithStyle(xlsx.StyleHeader)
+}
+
+// round1 保留一位小数
+func round1(f float64) float64 {
+	return float64(int(f*10+0.5)) / 10
+}
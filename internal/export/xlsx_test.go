@@ -0,0 +1,69 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/collector"
+)
+
+func testSummary() *Summary {
+	reports := []collector.Report{
+		{Text: "2025-03-10 日报 修复登录", Link: "https://kpi.example.com/view?id=1", Date: time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)},
+		{Text: "2025-03-11 日报 联调接口", Date: time.Date(2025, 3, 11, 0, 0, 0, 0, time.Local)},
+	}
+	return &Summary{
+		Label:   "日报",
+		Period:  "2025-03-01 ~ 2025-03-31",
+		Reports: reports,
+		Months: []MonthSummary{{
+			Month:   "2025-03",
+			Reports: reports,
+			Tasks: []Task{
+				{Name: "登录模块", Weight: 67, Days: 2, Entries: []string{"修复登录", "登录限流"}},
+				{Name: "接口联调", Weight: 33, Days: 1, Entries: []string{"联调接口"}},
+			},
+		}},
+	}
+}
+
+func TestWriteXLSXSummarySheet(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteXLSX(testSummary(), &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sheet string
+	for _, f := range z.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, _ := f.Open()
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			sheet = string(data)
+		}
+	}
+
+	for _, want := range []string{
+		"任务成果情况",
+		`<c r="C2" s="3"><v>0.67</v></c>`,
+		`<c r="C3" s="3"><v>0.33</v></c>`,
+		"修复登录\n登录限流",
+		`<f>SUM(C2:C3)</f><v>1</v>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("汇总表缺少 %q", want)
+		}
+	}
+	// 汇总表只有四列，权重与其他导出一致，不再附带无用的涉及天数列
+	if strings.Contains(sheet, "涉及天数") || strings.Contains(sheet, `r="E`) {
+		t.Errorf("汇总表不应有第五列:\n%s", sheet)
+	}
+}
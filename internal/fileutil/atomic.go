@@ -0,0 +1,180 @@
+package fileutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 目标文件已存在时的处理方式
+const (
+	ConflictOverwrite = "overwrite" // 覆盖，旧文件自动备份
+	ConflictVersion   = "version"   // 另存为 "文件名 (2).md"
+	ConflictFail      = "fail"      // 报错，不写入
+)
+
+// ConflictModes 返回所有冲突处理方式
+func ConflictModes() []string {
+	return []string{ConflictOverwrite, ConflictVersion, ConflictFail}
+}
+
+// ParseConflict 校验冲突处理方式，为空时返回 overwrite
+func ParseConflict(mode string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", ConflictOverwrite:
+		return ConflictOverwrite, nil
+	case ConflictVersion:
+		return ConflictVersion, nil
+	case ConflictFail:
+		return ConflictFail, nil
+	}
+	return "", fmt.Errorf("不支持的 on_conflict: %s（可选 %s）", mode, strings.Join(ConflictModes(), "、"))
+}
+
+// Options 导出文件的写入选项
+type Options struct {
+	OnConflict string // overwrite / version / fail，为空按 overwrite 处理
+	BackupDir  string // 被覆盖文件的备份根目录，为空则不备份
+}
+
+// File 先写入同目录的临时文件，Commit 时再重命名为目标文件，失败不会留下半截文件
+type File struct {
+	*os.File
+	target string
+	opts   Options
+	done   bool
+}
+
+// CheckConflict 在开始耗时操作前检查：on_conflict=fail 且目标已存在时返回错误
+func CheckConflict(target string, opts Options) error {
+	if opts.OnConflict == ConflictFail && exists(target) {
+		return conflictError(target)
+	}
+	return nil
+}
+
+// Create 创建导出文件（写入临时文件，Commit 后才替换目标文件）
+func Create(target string, opts Options) (*File, error) {
+	if err := CheckConflict(target, opts); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建输出目录失败: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时文件失败: %w", err)
+	}
+
+	return &File{File: tmp, target: target, opts: opts}, nil
+}
+
+// Commit 落盘并替换目标文件，返回最终写入的路径（version 模式下可能与目标不同）
+func (f *File) Commit() (string, error) {
+	if f.done {
+		return "", fmt.Errorf("文件已提交: %s", f.target)
+	}
+	f.done = true
+	tmp := f.File.Name()
+
+	if err := f.File.Sync(); err != nil {
+		f.File.Close()
+		os.Remove(tmp)
+		return "", fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := f.File.Close(); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := os.Chmod(tmp, 0644); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("设置文件权限失败: %w", err)
+	}
+
+	target := f.target
+	if exists(target) {
+		switch f.opts.OnConflict {
+		case ConflictFail:
+			os.Remove(tmp)
+			return "", conflictError(target)
+		case ConflictVersion:
+			target = nextName(target)
+		default:
+			if f.opts.BackupDir != "" {
+				if _, err := Backup(f.opts.BackupDir, target); err != nil {
+					os.Remove(tmp)
+					return "", err
+				}
+			}
+		}
+	}
+
+	if err := os.Rename(tmp, target); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("保存文件失败: %w", err)
+	}
+	return target, nil
+}
+
+// Abort 放弃写入并删除临时文件，Commit 之后调用无副作用，可直接 defer
+func (f *File) Abort() {
+	if f.done {
+		return
+	}
+	f.done = true
+	f.File.Close()
+	os.Remove(f.File.Name())
+}
+
+// WriteFile 原子地写入整个文件（临时文件 + 重命名），用于状态文件
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// nextName 返回不存在的文件名：日报详情.md -> 日报详情 (2).md
+func nextName(path string) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, i, ext)
+		if !exists(candidate) {
+			return candidate
+		}
+	}
+}
+
+// conflictError 目标文件已存在的错误
+func conflictError(path string) error {
+	return fmt.Errorf("文件已存在: %s（on_conflict=fail），可改用 overwrite 或 version", path)
+}
+
+// exists 判断文件是否存在
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,208 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseConflict(t *testing.T) {
+	tests := []struct {
+		mode, want string
+		wantErr    bool
+	}{
+		{"", ConflictOverwrite, false},
+		{"overwrite", ConflictOverwrite, false},
+		{" Version ", ConflictVersion, false},
+		{"FAIL", ConflictFail, false},
+		{"skip", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseConflict(tt.mode)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseConflict(%q) = %q, %v，期望 %q（出错 %v）", tt.mode, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCommitConflictModes(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		existing []string // 写入前已存在的文件
+		wantPath string   // 最终写入的文件名，为空表示应报错
+		backups  int      // 应产生的备份数
+	}{
+		{"目标不存在", ConflictFail, nil, "日报.md", 0},
+		{"overwrite 覆盖并备份", ConflictOverwrite, []string{"日报.md"}, "日报.md", 1},
+		{"version 另存", ConflictVersion, []string{"日报.md"}, "日报 (2).md", 0},
+		{"version 跳过已有版本号", ConflictVersion, []string{"日报.md", "日报 (2).md"}, "日报 (3).md", 0},
+		{"fail 报错", ConflictFail, []string{"日报.md"}, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			backupDir := filepath.Join(dir, VersionsDirName)
+			target := filepath.Join(dir, "out", "日报.md")
+			for _, name := range tt.existing {
+				writeTestFile(t, filepath.Join(dir, "out", name), "旧内容")
+			}
+
+			opts := Options{OnConflict: tt.mode, BackupDir: backupDir}
+			f, err := Create(target, opts)
+			if tt.wantPath == "" {
+				if err == nil || !strings.Contains(err.Error(), "文件已存在") {
+					t.Fatalf("Create() 错误 = %v，期望文件已存在", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Abort()
+			f.WriteString("新内容")
+
+			got, err := f.Commit()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := filepath.Join(dir, "out", tt.wantPath); got != want {
+				t.Errorf("Commit() = %s，期望 %s", got, want)
+			}
+			if data, _ := os.ReadFile(got); string(data) != "新内容" {
+				t.Errorf("写入内容 = %q", data)
+			}
+
+			versions, err := ListVersions(backupDir, target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(versions) != tt.backups {
+				t.Errorf("备份数 = %d，期望 %d", len(versions), tt.backups)
+			}
+			assertNoTemp(t, filepath.Join(dir, "out"))
+		})
+	}
+}
+
+// fail 模式下，Create 之后目标被其他进程创建，Commit 也应报错且不覆盖
+func TestCommitFailWhenTargetAppears(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "日报.md")
+
+	f, err := Create(target, Options{OnConflict: ConflictFail})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("新内容")
+	writeTestFile(t, target, "旧内容")
+
+	if _, err := f.Commit(); err == nil {
+		t.Fatal("Commit() 应报错")
+	}
+	if data, _ := os.ReadFile(target); string(data) != "旧内容" {
+		t.Errorf("目标文件被覆盖: %q", data)
+	}
+	assertNoTemp(t, dir)
+}
+
+func TestAbort(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "日报.md")
+
+	f, err := Create(target, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("半截内容")
+	f.Abort()
+
+	if exists(target) {
+		t.Error("Abort 后不应生成目标文件")
+	}
+	assertNoTemp(t, dir)
+	if _, err := f.Commit(); err == nil {
+		t.Error("Abort 后 Commit 应报错")
+	}
+}
+
+func TestNextName(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "a.md"), "")
+	writeTestFile(t, filepath.Join(dir, "a (2).md"), "")
+	writeTestFile(t, filepath.Join(dir, "noext"), "")
+
+	tests := []struct {
+		path, want string
+	}{
+		{"a.md", "a (3).md"},
+		{"b.md", "b (2).md"},
+		{"noext", "noext (2)"},
+	}
+	for _, tt := range tests {
+		if got := nextName(filepath.Join(dir, tt.path)); got != filepath.Join(dir, tt.want) {
+			t.Errorf("nextName(%s) = %s，期望 %s", tt.path, filepath.Base(got), tt.want)
+		}
+	}
+}
+
+func TestRestore(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, VersionsDirName)
+	target := filepath.Join(dir, "日报.md")
+
+	writeTestFile(t, target, "第一版")
+	if _, err := Backup(root, target); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, target, "第二版")
+
+	versions, err := ListVersions(root, target)
+	if err != nil || len(versions) != 1 {
+		t.Fatalf("ListVersions() = %v, %v", versions, err)
+	}
+
+	backup, err := Restore(root, target, versions[0].Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "第一版" {
+		t.Errorf("恢复后内容 = %q，期望 第一版", data)
+	}
+	if data, _ := os.ReadFile(backup); string(data) != "第二版" {
+		t.Errorf("恢复前的备份内容 = %q，期望 第二版", data)
+	}
+	if versions, _ := ListVersions(root, target); len(versions) != 2 {
+		t.Errorf("恢复后版本数 = %d，期望 2", len(versions))
+	}
+
+	for _, name := range []string{"", "../日报.md", "不存在.md"} {
+		if _, err := Restore(root, target, name); err == nil {
+			t.Errorf("Restore(%q) 应报错", name)
+		}
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// assertNoTemp 检查目录中没有残留的临时文件
+func assertNoTemp(t *testing.T, dir string) {
+	t.Helper()
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("残留临时文件: %s", entry.Name())
+		}
+	}
+}
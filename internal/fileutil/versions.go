@@ -0,0 +1,143 @@
+package fileutil
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// VersionsDirName 数据目录下保存导出文件历史版本的子目录
+const VersionsDirName = "versions"
+
+// versionLayout 历史版本文件名中的时间格式
+const versionLayout = "20060102-150405"
+
+// Version 导出文件的一个历史版本
+type Version struct {
+	Name    string // 版本名，即备份文件名，例如 20250310-150405.md
+	Path    string
+	SavedAt time.Time
+	Size    int64
+}
+
+// versionDir 返回文件的备份目录：<root>/<文件名>-<完整路径摘要>，同名文件在不同目录不会混在一起
+func versionDir(root, target string) string {
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		abs = target
+	}
+	sum := sha1.Sum([]byte(abs))
+	return filepath.Join(root, filepath.Base(target)+"-"+hex.EncodeToString(sum[:4]))
+}
+
+// Backup 将现有文件复制到备份目录，返回备份路径
+func Backup(root, target string) (string, error) {
+	dir := versionDir(root, target)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建备份目录失败: %w", err)
+	}
+
+	ext := filepath.Ext(target)
+	name := time.Now().Format(versionLayout)
+	backup := filepath.Join(dir, name+ext)
+	for i := 2; exists(backup); i++ {
+		backup = filepath.Join(dir, fmt.Sprintf("%s-%d%s", name, i, ext))
+	}
+
+	if err := copyFile(target, backup); err != nil {
+		return "", fmt.Errorf("备份 %s 失败: %w", target, err)
+	}
+	return backup, nil
+}
+
+// ListVersions 返回文件的历史版本，最新的在前
+func ListVersions(root, target string) ([]Version, error) {
+	entries, err := os.ReadDir(versionDir(root, target))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取备份目录失败: %w", err)
+	}
+
+	var versions []Version
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		stamp := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		savedAt, err := time.ParseInLocation(versionLayout, stamp[:min(len(stamp), len(versionLayout))], time.Local)
+		if err != nil {
+			savedAt = info.ModTime()
+		}
+		versions = append(versions, Version{
+			Name:    entry.Name(),
+			Path:    filepath.Join(versionDir(root, target), entry.Name()),
+			SavedAt: savedAt,
+			Size:    info.Size(),
+		})
+	}
+
+	// 同一秒的备份带 -2、-3 后缀，去掉扩展名比较才能排在前面
+	stem := func(name string) string { return strings.TrimSuffix(name, filepath.Ext(name)) }
+	sort.Slice(versions, func(i, j int) bool {
+		return stem(versions[i].Name) > stem(versions[j].Name)
+	})
+	return versions, nil
+}
+
+// Restore 用历史版本替换当前文件，当前文件会先被备份，返回被备份的路径（文件不存在时为空）
+func Restore(root, target, name string) (string, error) {
+	if name == "" || filepath.Base(name) != name {
+		return "", fmt.Errorf("版本名无效: %q", name)
+	}
+	src := filepath.Join(versionDir(root, target), name)
+	if !exists(src) {
+		return "", fmt.Errorf("没有找到版本 %s，可用 list_export_versions 查看", name)
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("读取版本失败: %w", err)
+	}
+
+	var backup string
+	if exists(target) {
+		if backup, err = Backup(root, target); err != nil {
+			return "", err
+		}
+	}
+	if err := WriteFile(target, data, 0644); err != nil {
+		return "", fmt.Errorf("恢复文件失败: %w", err)
+	}
+	return backup, nil
+}
+
+// copyFile 复制文件内容
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,494 @@
+package htmlmd
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	spaceRe     = regexp.MustCompile(`[ \t\r\n\f]+`)
+	blankLineRe = regexp.MustCompile(`\n{3,}`)
+	// lineStartRe 行首会被当作 Markdown 语法的字符：标题、引用、列表
+	lineStartRe = regexp.MustCompile(`(?m)^(\s*)([#>+-]|\d+\.)(\s)`)
+)
+
+// Markdown 把 HTML 片段转换为 Markdown：保留段落、换行、有序/无序列表、表格、代码、链接和图片，
+// 相对地址按 base 转换为完整地址。标题转换为加粗段落，避免打乱导出文件自身的标题层级
+func Markdown(sel *goquery.Selection, base *url.URL) string {
+	r := &renderer{base: base}
+	return r.render(sel)
+}
+
+// Text 把 HTML 片段转换为纯文本：保留段落、换行和列表的分行，去掉格式标记，用于搜索和统计
+func Text(sel *goquery.Selection) string {
+	r := &renderer{plain: true}
+	return r.render(sel)
+}
+
+// renderer 转换器，plain 为纯文本模式
+type renderer struct {
+	base  *url.URL
+	plain bool
+}
+
+// render 转换选中的节点
+func (r *renderer) render(sel *goquery.Selection) string {
+	var blocks []string
+	for _, n := range sel.Nodes {
+		blocks = append(blocks, r.blocks(n)...)
+	}
+	return finish(strings.Join(blocks, "\n\n"))
+}
+
+// blocks 把节点的子节点转换为块：连续的行内内容合并为一个段落，块级元素各自成块
+func (r *renderer) blocks(parent *html.Node) []string {
+	var (
+		blocks []string
+		inline strings.Builder
+	)
+	flush := func() {
+		if text := strings.TrimSpace(inline.String()); text != "" {
+			blocks = append(blocks, r.paragraph(text))
+		}
+		inline.Reset()
+	}
+
+	for n := parent.FirstChild; n != nil; n = n.NextSibling {
+		if n.Type == html.ElementNode && isBlock(n.DataAtom) {
+			flush()
+			if b := r.block(n); b != "" {
+				blocks = append(blocks, b)
+			}
+			continue
+		}
+		inline.WriteString(r.inline(n))
+	}
+	flush()
+	return blocks
+}
+
+// block 转换块级元素
+func (r *renderer) block(n *html.Node) string {
+	switch n.DataAtom {
+	case atom.Ul, atom.Ol:
+		return r.list(n)
+	case atom.Table:
+		return r.table(n)
+	case atom.Pre:
+		return r.pre(n)
+	case atom.Hr:
+		if r.plain {
+			return ""
+		}
+		return "***"
+	case atom.Blockquote:
+		content := strings.Join(r.blocks(n), "\n\n")
+		if r.plain || content == "" {
+			return content
+		}
+		return prefixLines(content, "> ", "> ")
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		text := strings.TrimSpace(r.inlineChildren(n))
+		if r.plain || text == "" {
+			return text
+		}
+		return "**" + text + "**"
+	case atom.Script, atom.Style, atom.Noscript, atom.Head, atom.Template:
+		return ""
+	}
+	return strings.Join(r.blocks(n), "\n\n")
+}
+
+// list 转换有序/无序列表，嵌套列表和多段内容按标记宽度缩进
+func (r *renderer) list(n *html.Node) string {
+	index := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil {
+		index = start
+	}
+
+	var items []string
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode {
+			continue
+		}
+		if li.DataAtom == atom.Ul || li.DataAtom == atom.Ol {
+			// 不规范的 HTML 中嵌套列表可能直接放在 ul 下
+			if nested := r.list(li); nested != "" && len(items) > 0 {
+				items[len(items)-1] += "\n" + prefixLines(nested, "   ", "   ")
+			}
+			continue
+		}
+
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = strconv.Itoa(index) + ". "
+			index++
+		}
+		content := r.listItem(li)
+		items = append(items, prefixLines(content, marker, strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(items, "\n")
+}
+
+// listItem 列表项内容：行内内容和嵌套列表之间不空行，其余块之间空行
+func (r *renderer) listItem(li *html.Node) string {
+	var b strings.Builder
+	prevList := false
+	for i, block := range r.blocks(li) {
+		isList := isListBlock(block)
+		switch {
+		case i == 0:
+		case isList || prevList:
+			b.WriteString("\n")
+		default:
+			b.WriteString("\n\n")
+		}
+		b.WriteString(block)
+		prevList = isList
+	}
+	return b.String()
+}
+
+// table 转换为 GFM 表格，第一行作为表头；纯文本模式下单元格用 | 分隔
+func (r *renderer) table(n *html.Node) string {
+	var rows [][]string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.DataAtom {
+			case atom.Tr:
+				var row []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type == html.ElementNode && (cell.DataAtom == atom.Td || cell.DataAtom == atom.Th) {
+						row = append(row, r.cell(cell))
+					}
+				}
+				if len(row) > 0 {
+					rows = append(rows, row)
+				}
+			case atom.Table:
+				// 嵌套表格拍平到单元格中由 cell 处理，这里不再深入
+			default:
+				walk(c)
+			}
+		}
+	}
+	walk(n)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	cols := 0
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+	lines := make([]string, 0, len(rows)+1)
+	for i, row := range rows {
+		for len(row) < cols {
+			row = append(row, "")
+		}
+		if r.plain {
+			lines = append(lines, strings.TrimSpace(strings.Join(row, " | ")))
+			continue
+		}
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", cols))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cell 单元格内容压成一行，换行在 Markdown 中写为 <br>
+func (r *renderer) cell(n *html.Node) string {
+	text := strings.Join(r.blocks(n), "\n")
+	if r.plain {
+		return strings.Join(strings.Fields(text), " ")
+	}
+	text = strings.ReplaceAll(text, "|", `\|`)
+	lines := strings.Split(text, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	return strings.Join(lines, "<br>")
+}
+
+// pre 转换代码块，保留原始空白
+func (r *renderer) pre(n *html.Node) string {
+	code := strings.Trim(rawText(n), "\n")
+	if r.plain || code == "" {
+		return code
+	}
+
+	lang := ""
+	for _, node := range []*html.Node{n, firstElement(n, atom.Code)} {
+		if node == nil {
+			continue
+		}
+		for _, class := range strings.Fields(attr(node, "class")) {
+			if l, ok := strings.CutPrefix(class, "language-"); ok {
+				lang = l
+			}
+		}
+	}
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + lang + "\n" + code + "\n" + fence
+}
+
+// paragraph 处理段落：合并多余空白，Markdown 模式下转义行首会被误认为语法的字符
+func (r *renderer) paragraph(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = strings.Join(lines, "\n")
+	if r.plain {
+		return text
+	}
+	text = lineStartRe.ReplaceAllStringFunc(text, func(m string) string {
+		if i := strings.Index(m, "."); i > 0 {
+			return m[:i] + `\` + m[i:]
+		}
+		trimmed := strings.TrimLeft(m, " \t")
+		return m[:len(m)-len(trimmed)] + `\` + trimmed
+	})
+	return strings.ReplaceAll(text, "\n", "  \n")
+}
+
+// inline 转换行内节点
+func (r *renderer) inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		text := spaceRe.ReplaceAllString(n.Data, " ")
+		if r.plain {
+			return text
+		}
+		return escape(text)
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		return "\n"
+	case atom.Script, atom.Style, atom.Noscript, atom.Template:
+		return ""
+	case atom.Img:
+		return r.image(n)
+	case atom.A:
+		return r.link(n)
+	case atom.Code, atom.Kbd, atom.Samp, atom.Tt:
+		code := spaceRe.ReplaceAllString(rawText(n), " ")
+		if r.plain || strings.TrimSpace(code) == "" {
+			return code
+		}
+		fence := "`"
+		for strings.Contains(code, fence) {
+			fence += "`"
+		}
+		if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+			code = " " + code + " "
+		}
+		return fence + code + fence
+	case atom.Strong, atom.B:
+		return r.wrap(n, "**")
+	case atom.Em, atom.I:
+		return r.wrap(n, "*")
+	case atom.Del, atom.S, atom.Strike:
+		return r.wrap(n, "~~")
+	}
+	if isBlock(n.DataAtom) {
+		// 行内上下文中出现的块级元素（例如单元格中的 p）按换行处理
+		return "\n" + strings.Join(r.blocks(n), "\n") + "\n"
+	}
+	return r.inlineChildren(n)
+}
+
+// inlineChildren 转换子节点的行内内容
+func (r *renderer) inlineChildren(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(r.inline(c))
+	}
+	return b.String()
+}
+
+// wrap 用强调标记包住内容，标记放在首尾空白之内
+func (r *renderer) wrap(n *html.Node, mark string) string {
+	content := r.inlineChildren(n)
+	trimmed := strings.TrimSpace(content)
+	if r.plain || trimmed == "" {
+		return content
+	}
+	lead := content[:len(content)-len(strings.TrimLeft(content, " \n"))]
+	trail := content[len(strings.TrimRight(content, " \n")):]
+	return lead + mark + trimmed + mark + trail
+}
+
+// link 转换链接；文字与地址相同时写为自动链接
+func (r *renderer) link(n *html.Node) string {
+	text := r.inlineChildren(n)
+	href := r.resolve(attr(n, "href"))
+	if r.plain || href == "" {
+		return text
+	}
+	label := strings.TrimSpace(text)
+	switch {
+	case label == "":
+		return ""
+	case label == href || label == escape(href):
+		return "<" + href + ">"
+	}
+	return "[" + label + "](" + destination(href) + ")"
+}
+
+// image 转换图片，纯文本模式下只保留说明文字
+func (r *renderer) image(n *html.Node) string {
+	alt := strings.TrimSpace(attr(n, "alt"))
+	if r.plain {
+		return alt
+	}
+	src := r.resolve(attr(n, "src"))
+	if src == "" {
+		return ""
+	}
+	return "![" + escape(alt) + "](" + destination(src) + ")"
+}
+
+// resolve 把相对地址转换为完整地址，忽略锚点和脚本链接
+func (r *renderer) resolve(ref string) string {
+	ref = strings.TrimSpace(ref)
+	switch {
+	case ref == "", strings.HasPrefix(ref, "#"), strings.HasPrefix(strings.ToLower(ref), "javascript:"):
+		return ""
+	case strings.HasPrefix(ref, "data:"), r.base == nil:
+		return ref
+	}
+	u, err := r.base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+// escape 转义行内文本中的 Markdown 标记
+func escape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`).Replace(text)
+}
+
+// destination 链接地址中有空格或括号时用尖括号包起来
+func destination(u string) string {
+	if strings.ContainsAny(u, " ()") {
+		return "<" + strings.ReplaceAll(u, ">", "%3E") + ">"
+	}
+	return u
+}
+
+// finish 整理输出：去掉行尾空白（保留 Markdown 硬换行），合并多余空行
+func finish(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasSuffix(line, "  ") && strings.TrimSpace(line) != "" {
+			lines[i] = strings.TrimRight(line, " \t") + "  "
+		} else {
+			lines[i] = strings.TrimRight(line, " \t")
+		}
+	}
+	text = blankLineRe.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// prefixLines 给第一行加 first 前缀，其余非空行加 rest 前缀
+func prefixLines(text, first, rest string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			lines[i] = first + line
+		case line != "":
+			lines[i] = rest + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isListBlock 判断块是否为列表
+func isListBlock(block string) bool {
+	if strings.HasPrefix(block, "- ") {
+		return true
+	}
+	i := strings.Index(block, ". ")
+	if i <= 0 {
+		return false
+	}
+	_, err := strconv.Atoi(block[:i])
+	return err == nil
+}
+
+// rawText 返回节点的原始文本，br 转为换行
+func rawText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+		case n.Type == html.ElementNode && n.DataAtom == atom.Br:
+			b.WriteString("\n")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// firstElement 返回第一个指定标签的子孙元素
+func firstElement(n *html.Node, a atom.Atom) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.DataAtom == a {
+			return c
+		}
+		if found := firstElement(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// attr 返回元素属性
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// isBlock 判断是否为块级元素
+func isBlock(a atom.Atom) bool {
+	switch a {
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Header, atom.Footer, atom.Main, atom.Aside, atom.Nav,
+		atom.Ul, atom.Ol, atom.Li, atom.Dl, atom.Dt, atom.Dd, atom.Table, atom.Thead, atom.Tbody, atom.Tfoot, atom.Tr,
+		atom.Pre, atom.Blockquote, atom.Hr, atom.Figure, atom.Figcaption, atom.Address, atom.Details, atom.Summary,
+		atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Script, atom.Style, atom.Noscript, atom.Head, atom.Template:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package htmlmd
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// parse 解析 HTML 片段，返回 body 的内容
+func parse(t *testing.T, fragment string) *goquery.Selection {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader("<body>" + fragment + "</body>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc.Find("body")
+}
+
+func TestMarkdown(t *testing.T) {
+	base, _ := url.Parse("https://kpi.example.com/report/report-daily/index")
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "段落和换行",
+			html: "<p>第一段<br>第二行</p><p>  第二段  </p>",
+			want: "第一段  \n第二行\n\n第二段",
+		},
+		{
+			name: "无序列表",
+			html: "<ul><li>一</li><li>二</li></ul>",
+			want: "- 一\n- 二",
+		},
+		{
+			name: "有序列表和起始序号",
+			html: `<ol start="3"><li>三</li><li>四</li></ol>`,
+			want: "3. 三\n4. 四",
+		},
+		{
+			name: "嵌套列表",
+			html: "<ul><li>父<ol><li>子一</li><li>子二</li></ol></li><li>兄</li></ul>",
+			want: "- 父\n  1. 子一\n  2. 子二\n- 兄",
+		},
+		{
+			name: "表格",
+			html: "<table><tr><th>事项</th><th>工时</th></tr><tr><td>联调<br>测试</td><td>3</td></tr><tr><td>a|b</td></tr></table>",
+			want: "| 事项 | 工时 |\n| --- | --- |\n| 联调<br>测试 | 3 |\n| a\\|b |  |",
+		},
+		{
+			name: "代码块",
+			html: "<pre><code class=\"language-go\">if x {\n    return\n}</code></pre>",
+			want: "```go\nif x {\n    return\n}\n```",
+		},
+		{
+			name: "代码块中含有围栏",
+			html: "<pre>```\nx\n```</pre>",
+			want: "````\n```\nx\n```\n````",
+		},
+		{
+			name: "行内代码和强调",
+			html: "<p>修复 <code>login</code> <b>接口</b> <em>超时</em> <del>旧</del></p>",
+			want: "修复 `login` **接口** *超时* ~~旧~~",
+		},
+		{
+			name: "相对链接和自动链接",
+			html: `<p><a href="view?id=1">原文</a> <a href="https://e.com/a">https://e.com/a</a> <a href="#top">锚点</a> <a href="javascript:void(0)">脚本</a></p>`,
+			want: "[原文](https://kpi.example.com/report/report-daily/view?id=1) <https://e.com/a> 锚点 脚本",
+		},
+		{
+			name: "图片",
+			html: `<p><img src="/uploads/a b.png" alt="截图"></p>`,
+			want: "![截图](https://kpi.example.com/uploads/a%20b.png)",
+		},
+		{
+			name: "标题和分隔线",
+			html: "<h3>明日计划</h3><hr><p>写文档</p>",
+			want: "**明日计划**\n\n***\n\n写文档",
+		},
+		{
+			name: "转义行首语法和行内标记",
+			html: "<p># 不是标题<br>- 不是列表<br>1. 不是序号<br>a*b [c]</p>",
+			want: "\\# 不是标题  \n\\- 不是列表  \n1\\. 不是序号  \na\\*b \\[c\\]",
+		},
+		{
+			name: "忽略脚本和样式",
+			html: "<p>正文</p><script>alert(1)</script><style>p{}</style>",
+			want: "正文",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markdown(parse(t, tt.html), base); got != tt.want {
+				t.Errorf("Markdown()\n得到：%q\n期望：%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"段落", "<p>第一段<br>第二行</p><p>第二段</p>", "第一段\n第二行\n\n第二段"},
+		{"列表", "<ol><li>修复 <b>登录</b></li><li>评审 <a href=\"/pr\">PR</a></li></ol>", "1. 修复 登录\n2. 评审 PR"},
+		{"表格", "<table><tr><th>事项</th><th>工时</th></tr><tr><td>联调</td><td>3</td></tr></table>", "事项 | 工时\n联调 | 3"},
+		{"代码块", "<pre>x := 1\ny := 2</pre>", "x := 1\ny := 2"},
+		{"图片只保留说明", `<p>见 <img src="a.png" alt="截图"></p>`, "见 截图"},
+		{"标题", "<h2>明日计划</h2><p>写文档</p>", "明日计划\n\n写文档"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(parse(t, tt.html)); got != tt.want {
+				t.Errorf("Text()\n得到：%q\n期望：%q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Plain 应把 Markdown 的输出还原为与 Text 相同的纯文本
+func TestPlainMatchesText(t *testing.T) {
+	fragments := []string{
+		"<p>第一段<br>第二行</p><p>第二段</p>",
+		"<ul><li>修复 <code>login</code> <b>接口</b></li><li>评审 <a href=\"https://e.com/pr\">PR</a></li></ul>",
+		"<table><tr><th>事项</th><th>工时</th></tr><tr><td>联调</td><td>3</td></tr></table>",
+		"<pre class=\"language-go\">x := 1\n---\n## y</pre><p>结束</p>",
+		"<h3>明日计划</h3><hr><ol><li>写文档</li><li>a*b [c]</li></ol>",
+		"<p># 不是标题<br>1. 不是序号</p>",
+		`<p>见 <img src="https://e.com/a.png" alt="截图"> <a href="https://e.com">https://e.com</a></p>`,
+	}
+
+	for _, fragment := range fragments {
+		sel := parse(t, fragment)
+		md := Markdown(sel, nil)
+		if got, want := Plain(md), Text(sel); got != want {
+			t.Errorf("Plain(Markdown(%q))\n得到：%q\n期望：%q\nMarkdown：%q", fragment, got, want, md)
+		}
+	}
+}
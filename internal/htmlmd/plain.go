@@ -0,0 +1,65 @@
+package htmlmd
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// mdImageRe、mdLinkRe 图片和链接，保留说明文字
+	mdImageRe = regexp.MustCompile(`!\[((?:\\.|[^\]\\])*)\]\((?:<[^>]*>|[^)]*)\)`)
+	mdLinkRe  = regexp.MustCompile(`\[((?:\\.|[^\]\\])*)\]\((?:<[^>]*>|[^)]*)\)`)
+	// mdAutoLinkRe 自动链接 <https://...>
+	mdAutoLinkRe = regexp.MustCompile(`<((?:https?|mailto):[^>]*)>`)
+	// mdMarkRe 加粗、斜体、删除线和行内代码标记（不含转义的字符）
+	mdMarkRe = regexp.MustCompile(`(^|[^\\])(\*\*|\*|~~|` + "`" + `)`)
+	// mdTableSepRe 表格表头分隔行
+	mdTableSepRe = regexp.MustCompile(`^\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?$`)
+	// mdEscapeRe 转义字符
+	mdEscapeRe = regexp.MustCompile(`\\([\\*` + "`" + `\[\]<>#+\-.!_|~])`)
+)
+
+// Plain 把 Markdown 正文（Markdown 的输出）还原为与 Text 一致的纯文本，用于从导出文件读回的报告
+func Plain(md string) string {
+	var (
+		lines []string
+		fence string
+	)
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if trimmed == fence {
+				fence = ""
+				continue
+			}
+			lines = append(lines, strings.TrimRight(line, "\r"))
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") {
+			fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, "`"))]
+			continue
+		}
+		if trimmed == "***" || mdTableSepRe.MatchString(trimmed) {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "|") && strings.HasSuffix(trimmed, "|") {
+			line = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(trimmed, "|"), "|"))
+			line = strings.ReplaceAll(line, "<br>", " ")
+		}
+		lines = append(lines, plainInline(strings.TrimRight(line, " \t\r")))
+	}
+	return finish(strings.Join(lines, "\n"))
+}
+
+// plainInline 去掉一行中的行内标记
+func plainInline(line string) string {
+	line = mdImageRe.ReplaceAllString(line, "$1")
+	line = mdLinkRe.ReplaceAllString(line, "$1")
+	line = mdAutoLinkRe.ReplaceAllString(line, "$1")
+	// 相邻标记（如 ***）需要多次替换
+	for prev := ""; prev != line; {
+		prev = line
+		line = mdMarkRe.ReplaceAllString(line, "$1")
+	}
+	return mdEscapeRe.ReplaceAllString(line, "$1")
+}
@@ -0,0 +1,302 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/fileutil"
+)
+
+// DirName 状态目录下保存任务状态的子目录
+const DirName = "jobs"
+
+// 任务状态
+const (
+	StatusRunning     = "running"
+	StatusSucceeded   = "succeeded"
+	StatusFailed      = "failed"
+	StatusCanceled    = "canceled"
+	StatusInterrupted = "interrupted" // 服务重启时仍在运行的任务
+)
+
+// Job 后台任务
+type Job struct {
+	ID         string            `json:"id"`
+	Kind       string            `json:"kind"`
+	Params     map[string]string `json:"params,omitempty"`
+	Status     string            `json:"status"`
+	Stage      string            `json:"stage,omitempty"`
+	Done       int               `json:"done"`
+	Total      int               `json:"total"`
+	Partial    []string          `json:"partial,omitempty"` // 已完成部分的摘要
+	Result     string            `json:"result,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	FinishedAt time.Time         `json:"finished_at,omitzero"`
+}
+
+// Finished 判断任务是否已结束
+func (j *Job) Finished() bool {
+	return j.Status != StatusRunning
+}
+
+// Summary 返回任务的一行摘要
+func (j *Job) Summary() string {
+	line := fmt.Sprintf("%s [%s] %s", j.ID, j.Kind, j.Status)
+	if j.Total > 0 {
+		line += fmt.Sprintf(" %d/%d", j.Done, j.Total)
+	}
+	if j.Stage != "" {
+		line += " " + j.Stage
+	}
+	return line + "，创建于 " + j.CreatedAt.Format("2006-01-02 15:04:05")
+}
+
+// Format 返回任务的详细状态
+func (j *Job) Format() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "任务：%s（%s）\n状态：%s\n", j.ID, j.Kind, j.Status)
+	if j.Stage != "" {
+		fmt.Fprintf(&b, "阶段：%s\n", j.Stage)
+	}
+	if j.Total > 0 {
+		fmt.Fprintf(&b, "进度：%d/%d\n", j.Done, j.Total)
+	}
+	fmt.Fprintf(&b, "创建时间：%s\n", j.CreatedAt.Format("2006-01-02 15:04:05"))
+	if !j.FinishedAt.IsZero() {
+		fmt.Fprintf(&b, "结束时间：%s（耗时 %s）\n", j.FinishedAt.Format("2006-01-02 15:04:05"),
+			j.FinishedAt.Sub(j.CreatedAt).Round(time.Second))
+	}
+	if j.Error != "" {
+		fmt.Fprintf(&b, "错误：%s\n", j.Error)
+	}
+	if len(j.Partial) > 0 {
+		fmt.Fprintf(&b, "\n已完成部分：\n- %s\n", strings.Join(j.Partial, "\n- "))
+	}
+	return b.String()
+}
+
+// Progress 任务执行函数用来汇报进度
+type Progress struct {
+	m  *Manager
+	id string
+}
+
+// Stage 更新当前阶段说明
+func (p *Progress) Stage(stage string) {
+	p.m.update(p.id, func(j *Job) {
+		j.Stage = stage
+	})
+}
+
+// Step 更新完成数量，并记录一条已完成部分的摘要
+func (p *Progress) Step(done, total int, partial string) {
+	p.m.update(p.id, func(j *Job) {
+		j.Done, j.Total = done, total
+		if partial != "" {
+			j.Partial = append(j.Partial, partial)
+		}
+	})
+}
+
+// Func 任务执行函数，返回结果文本
+type Func func(ctx context.Context, p *Progress) (string, error)
+
+// Manager 后台任务管理器，任务状态保存在状态目录的 jobs/ 下
+type Manager struct {
+	dir     string
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	cancels map[string]context.CancelFunc
+}
+
+// NewManager 创建任务管理器并加载历史任务，上次未结束的任务标记为 interrupted
+func NewManager(stateDir string) (*Manager, error) {
+	m := &Manager{
+		dir:     filepath.Join(stateDir, DirName),
+		jobs:    make(map[string]*Job),
+		cancels: make(map[string]context.CancelFunc),
+	}
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建任务目录失败: %w", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(m.dir, "*.json"))
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil || job.ID == "" {
+			log.Printf("跳过无法解析的任务文件 %s: %v", path, err)
+			continue
+		}
+		if job.Status == StatusRunning {
+			job.Status = StatusInterrupted
+			job.Error = "服务重启，任务被中断"
+			job.FinishedAt = time.Now()
+			m.save(&job)
+		}
+		m.jobs[job.ID] = &job
+	}
+
+	return m, nil
+}
+
+// Start 启动后台任务并立即返回
+func (m *Manager) Start(kind string, params map[string]string, fn Func) (*Job, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:        id,
+		Kind:      kind,
+		Params:    params,
+		Status:    StatusRunning,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m.mu.Lock()
+	m.jobs[id] = job
+	m.cancels[id] = cancel
+	m.save(job)
+	snapshot := *job
+	m.mu.Unlock()
+
+	go m.run(ctx, id, fn)
+	return &snapshot, nil
+}
+
+// run 执行任务并记录结果
+func (m *Manager) run(ctx context.Context, id string, fn Func) {
+	result, err := func() (result string, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("任务异常退出: %v", r)
+			}
+		}()
+		return fn(ctx, &Progress{m: m, id: id})
+	}()
+
+	m.update(id, func(j *Job) {
+		j.Result = result
+		j.FinishedAt = time.Now()
+		switch {
+		case errors.Is(ctx.Err(), context.Canceled):
+			j.Status = StatusCanceled
+			j.Error = "任务已取消"
+		case err != nil:
+			j.Status = StatusFailed
+			j.Error = err.Error()
+		default:
+			j.Status = StatusSucceeded
+		}
+	})
+
+	m.mu.Lock()
+	if cancel := m.cancels[id]; cancel != nil {
+		cancel()
+	}
+	delete(m.cancels, id)
+	m.mu.Unlock()
+}
+
+// Get 返回任务快照
+func (m *Manager) Get(id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("任务 %s 不存在", id)
+	}
+	snapshot := *job
+	snapshot.Partial = append([]string(nil), job.Partial...)
+	return &snapshot, nil
+}
+
+// List 返回所有任务快照，最新的在前
+func (m *Manager) List() []*Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var list []*Job
+	for _, job := range m.jobs {
+		snapshot := *job
+		list = append(list, &snapshot)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+	return list
+}
+
+// Cancel 取消运行中的任务
+func (m *Manager) Cancel(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return fmt.Errorf("任务 %s 不存在", id)
+	}
+	cancel, running := m.cancels[id]
+	if !running || job.Finished() {
+		return fmt.Errorf("任务 %s 已结束（%s），无需取消", id, job.Status)
+	}
+	cancel()
+	return nil
+}
+
+// update 修改任务并保存
+func (m *Manager) update(id string, fn func(j *Job)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return
+	}
+	fn(job)
+	job.UpdatedAt = time.Now()
+	m.save(job)
+}
+
+// save 将任务写入 jobs/<id>.json，调用方需持有锁（或任务尚未共享）
+func (m *Manager) save(job *Job) {
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		log.Printf("序列化任务 %s 失败: %v", job.ID, err)
+		return
+	}
+	if err := fileutil.WriteFile(filepath.Join(m.dir, job.ID+".json"), data, 0644); err != nil {
+		log.Printf("保存任务 %s 失败: %v", job.ID, err)
+	}
+}
+
+// newID 生成任务 ID，例如 20250310-150405-a1b2c3
+func newID() (string, error) {
+	buf := make([]byte, 3)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成任务 ID 失败: %w", err)
+	}
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(buf), nil
+}
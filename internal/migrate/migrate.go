@@ -0,0 +1,362 @@
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/fileutil"
+)
+
+// ManifestFileName 数据目录下记录布局版本的文件
+const ManifestFileName = "manifest.json"
+
+// BackupDirName 数据目录下保存迁移前备份的子目录
+const BackupDirName = "backups"
+
+// backupLayout 备份目录名中的时间格式
+const backupLayout = "20060102-150405"
+
+// MaxBackups 最多保留的迁移前备份数，更早的备份会被删除
+const MaxBackups = 3
+
+// skipBackup 备份时跳过的目录：浏览器用户数据可以重新登录生成，体积大且迁移只会整体移动它
+var skipBackup = map[string]bool{
+	"browser_profile": true,
+}
+
+// Manifest 数据目录的布局版本和迁移记录
+type Manifest struct {
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+	History   []Record  `json:"history,omitempty"`
+}
+
+// Record 一次已执行的迁移
+type Record struct {
+	Version     int       `json:"version"`
+	Description string    `json:"description"`
+	AppliedAt   time.Time `json:"applied_at"`
+	Backup      string    `json:"backup,omitempty"`
+}
+
+// Migration 一次布局迁移，Apply 只能通过 Runner 操作文件，dry-run 时才能只列出计划
+type Migration struct {
+	Version     int
+	Description string
+	Apply       func(r *Runner) error
+}
+
+// CurrentVersion 当前程序使用的数据目录布局版本
+func CurrentVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// Step 一个迁移执行（或计划执行）的操作
+type Step struct {
+	Version     int
+	Description string
+	Actions     []string
+}
+
+// Result 迁移结果
+type Result struct {
+	Dir     string
+	From    int
+	To      int
+	DryRun  bool
+	Backup  string
+	Steps   []Step
+	Created bool // 新建的数据目录，只写入 manifest
+}
+
+// String 返回迁移结果说明
+func (r *Result) String() string {
+	var b strings.Builder
+	switch {
+	case r.Created:
+		fmt.Fprintf(&b, "数据目录 %s 为新目录，布局版本 %d\n", r.Dir, r.To)
+		return b.String()
+	case r.From == r.To:
+		fmt.Fprintf(&b, "数据目录 %s 已是最新布局（版本 %d），无需迁移\n", r.Dir, r.To)
+		return b.String()
+	case r.DryRun:
+		fmt.Fprintf(&b, "数据目录 %s 需要从版本 %d 迁移到 %d（dry-run，未做任何修改）\n", r.Dir, r.From, r.To)
+	default:
+		fmt.Fprintf(&b, "数据目录 %s 已从版本 %d 迁移到 %d\n", r.Dir, r.From, r.To)
+	}
+	if r.Backup != "" {
+		fmt.Fprintf(&b, "迁移前备份: %s\n", r.Backup)
+	}
+	for _, step := range r.Steps {
+		fmt.Fprintf(&b, "\n版本 %d：%s\n", step.Version, step.Description)
+		if len(step.Actions) == 0 {
+			b.WriteString("  - 无需改动\n")
+		}
+		for _, action := range step.Actions {
+			fmt.Fprintf(&b, "  - %s\n", action)
+		}
+	}
+	return b.String()
+}
+
+// Run 检查数据目录的布局版本并依次执行未完成的迁移，迁移前先备份数据目录（不含浏览器用户数据），只保留最近 MaxBackups 个备份
+//
+// dryRun 为 true 时只列出将要执行的操作，不修改任何文件
+func Run(dataDir string, dryRun bool) (*Result, error) {
+	result := &Result{Dir: dataDir, To: CurrentVersion(), DryRun: dryRun}
+
+	manifest, found, err := loadManifest(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		empty, err := isEmpty(dataDir)
+		if err != nil {
+			return nil, err
+		}
+		if empty {
+			// 新安装，直接使用最新布局
+			result.From, result.Created = result.To, true
+			if dryRun {
+				return result, nil
+			}
+			return result, saveManifest(dataDir, &Manifest{Version: result.To})
+		}
+	}
+
+	result.From = manifest.Version
+	if manifest.Version > result.To {
+		return nil, fmt.Errorf("数据目录 %s 的布局版本 %d 高于当前程序支持的版本 %d，请升级程序", dataDir, manifest.Version, result.To)
+	}
+	if manifest.Version == result.To {
+		return result, nil
+	}
+
+	stamp := time.Now().Format(backupLayout)
+	result.Backup = filepath.Join(dataDir, BackupDirName, fmt.Sprintf("layout-v%d-%s", manifest.Version, stamp))
+	if !dryRun {
+		log.Printf("迁移数据目录前备份到 %s", result.Backup)
+		if err := backup(dataDir, result.Backup); err != nil {
+			return nil, fmt.Errorf("备份数据目录失败，未做迁移: %w", err)
+		}
+		pruneBackups(filepath.Join(dataDir, BackupDirName), MaxBackups)
+	}
+
+	r := &Runner{dir: dataDir, dryRun: dryRun, moved: make(map[string]string)}
+	for _, m := range migrations {
+		if m.Version <= manifest.Version {
+			continue
+		}
+		r.actions = nil
+		if err := m.Apply(r); err != nil {
+			return result, fmt.Errorf("迁移到版本 %d（%s）失败: %w，可从 %s 恢复", m.Version, m.Description, err, result.Backup)
+		}
+		result.Steps = append(result.Steps, Step{Version: m.Version, Description: m.Description, Actions: r.actions})
+		if dryRun {
+			continue
+		}
+
+		manifest.Version = m.Version
+		manifest.History = append(manifest.History, Record{
+			Version:     m.Version,
+			Description: m.Description,
+			AppliedAt:   time.Now(),
+			Backup:      result.Backup,
+		})
+		if err := saveManifest(dataDir, manifest); err != nil {
+			return result, err
+		}
+		log.Printf("✓ 数据目录已迁移到版本 %d：%s", m.Version, m.Description)
+	}
+	return result, nil
+}
+
+// Runner 迁移中的文件操作，路径均相对数据目录；dry-run 时只记录操作，并记住移动过的文件以便后续迁移读取
+type Runner struct {
+	dir     string
+	dryRun  bool
+	actions []string
+	moved   map[string]string // dry-run 中 目标 -> 原路径
+}
+
+// Exists 判断文件是否存在（包括 dry-run 中移动过的文件）
+func (r *Runner) Exists(rel string) bool {
+	_, err := os.Stat(r.abs(rel))
+	return err == nil
+}
+
+// Glob 按模式匹配文件（包括 dry-run 中移动过的文件），返回相对路径
+func (r *Runner) Glob(pattern string) []string {
+	files, _ := filepath.Glob(filepath.Join(r.dir, pattern))
+	var matches []string
+	for _, file := range files {
+		if rel, err := filepath.Rel(r.dir, file); err == nil {
+			matches = append(matches, rel)
+		}
+	}
+	for dst := range r.moved {
+		if ok, _ := filepath.Match(pattern, dst); ok {
+			matches = append(matches, dst)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// ReadFile 读取文件
+func (r *Runner) ReadFile(rel string) ([]byte, error) {
+	return os.ReadFile(r.abs(rel))
+}
+
+// Move 移动文件或目录，目标已存在时报错
+func (r *Runner) Move(src, dst string) error {
+	r.actions = append(r.actions, fmt.Sprintf("移动 %s -> %s", src, dst))
+	if r.Exists(dst) {
+		return fmt.Errorf("%s 已存在，无法移动 %s", dst, src)
+	}
+	if r.dryRun {
+		r.moved[filepath.Clean(dst)] = r.abs(src)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(r.dir, dst)), 0755); err != nil {
+		return err
+	}
+	return os.Rename(filepath.Join(r.dir, src), filepath.Join(r.dir, dst))
+}
+
+// WriteFile 原子地写入文件
+func (r *Runner) WriteFile(rel, note string, data []byte, perm os.FileMode) error {
+	r.actions = append(r.actions, fmt.Sprintf("%s %s", note, rel))
+	if r.dryRun {
+		return nil
+	}
+	return fileutil.WriteFile(filepath.Join(r.dir, rel), data, perm)
+}
+
+// abs 返回文件的实际路径，dry-run 中移动过的文件返回原路径
+func (r *Runner) abs(rel string) string {
+	if src, ok := r.moved[filepath.Clean(rel)]; ok {
+		return src
+	}
+	return filepath.Join(r.dir, rel)
+}
+
+// loadManifest 读取 manifest，文件不存在时返回版本 0
+func loadManifest(dataDir string) (*Manifest, bool, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, ManifestFileName))
+	if os.IsNotExist(err) {
+		return &Manifest{}, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("读取 %s 失败: %w", ManifestFileName, err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, false, fmt.Errorf("解析 %s 失败: %w", ManifestFileName, err)
+	}
+	return &m, true, nil
+}
+
+// saveManifest 保存 manifest
+func saveManifest(dataDir string, m *Manifest) error {
+	m.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化 %s 失败: %w", ManifestFileName, err)
+	}
+	if err := fileutil.WriteFile(filepath.Join(dataDir, ManifestFileName), data, 0644); err != nil {
+		return fmt.Errorf("保存 %s 失败: %w", ManifestFileName, err)
+	}
+	return nil
+}
+
+// isEmpty 判断数据目录是否不存在或为空
+func isEmpty(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("读取数据目录失败: %w", err)
+	}
+	return len(entries) == 0, nil
+}
+
+// backup 复制数据目录到 dst，跳过 backups/ 和浏览器用户数据
+func backup(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if rel == BackupDirName || skipBackup[info.Name()] {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return copyFile(path, filepath.Join(dst, rel), info.Mode().Perm())
+	})
+}
+
+// pruneBackups 只保留最新的 keep 个迁移前备份，删除失败只记录日志
+func pruneBackups(dir string, keep int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), "layout-v") {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) <= keep {
+		return
+	}
+
+	// 目录名以 20060102-150405 结尾，按时间戳排序，最早的在前
+	stamp := func(name string) string { return name[max(0, len(name)-len(backupLayout)):] }
+	sort.Slice(names, func(i, j int) bool { return stamp(names[i]) < stamp(names[j]) })
+	for _, name := range names[:len(names)-keep] {
+		if err := os.RemoveAll(filepath.Join(dir, name)); err != nil {
+			log.Printf("⚠ 删除旧备份 %s 失败: %v", name, err)
+			continue
+		}
+		log.Printf("已删除旧备份 %s", name)
+	}
+}
+
+// copyFile 复制文件并保留权限
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,206 @@
+package migrate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const legacyCookies = `[{"name": "session", "value": "abc", "domain": "kpi.example.com"}]`
+
+// legacyLayout 创建版本 0 的数据目录：根目录下的 cookies.json 和 browser_profile/
+func legacyLayout(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "cookies.json"), legacyCookies)
+	writeTestFile(t, filepath.Join(dir, "browser_profile", "Default", "Preferences"), "{}")
+	writeTestFile(t, filepath.Join(dir, "search_index.json"), "{}")
+	return dir
+}
+
+func TestRunDryRun(t *testing.T) {
+	dir := legacyLayout(t)
+	before := listFiles(t, dir)
+
+	result, err := Run(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.From != 0 || result.To != CurrentVersion() || !result.DryRun {
+		t.Errorf("Result = %+v", result)
+	}
+
+	want := [][]string{
+		{"移动 cookies.json -> " + filepath.Join("profiles", "default", "cookies.json"),
+			"移动 browser_profile -> " + filepath.Join("profiles", "default", "browser_profile")},
+		// 版本 2 能看到版本 1 在 dry-run 中移动过的文件
+		{"转换格式 " + filepath.Join("profiles", "default", "cookies.json")},
+	}
+	var got [][]string
+	for _, step := range result.Steps {
+		got = append(got, step.Actions)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Steps\n得到：%q\n期望：%q", got, want)
+	}
+	if !strings.Contains(result.String(), "dry-run，未做任何修改") {
+		t.Errorf("String() = %s", result.String())
+	}
+
+	if after := listFiles(t, dir); !reflect.DeepEqual(after, before) {
+		t.Errorf("dry-run 修改了数据目录\n之前：%v\n之后：%v", before, after)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := legacyLayout(t)
+
+	result, err := Run(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 迁移后的布局
+	for _, rel := range []string{
+		"profiles/default/cookies.json",
+		"profiles/default/browser_profile/Default/Preferences",
+		"search_index.json",
+		ManifestFileName,
+	} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err != nil {
+			t.Errorf("迁移后缺少 %s", rel)
+		}
+	}
+	for _, rel := range []string{"cookies.json", "browser_profile"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err == nil {
+			t.Errorf("迁移后 %s 仍在根目录", rel)
+		}
+	}
+
+	var file cookiesV2
+	data, _ := os.ReadFile(filepath.Join(dir, "profiles", "default", "cookies.json"))
+	if err := json.Unmarshal(data, &file); err != nil || len(file.Cookies) != 1 || file.SavedAt.IsZero() {
+		t.Errorf("cookies.json 未转换为新格式: %s", data)
+	}
+
+	manifest, _, err := loadManifest(dir)
+	if err != nil || manifest.Version != CurrentVersion() || len(manifest.History) != len(migrations) {
+		t.Errorf("manifest = %+v, %v", manifest, err)
+	}
+
+	// 备份包含迁移前的文件，但不包含浏览器用户数据
+	if got := listFiles(t, result.Backup); !reflect.DeepEqual(got, []string{"cookies.json", "search_index.json"}) {
+		t.Errorf("备份内容 = %v", got)
+	}
+
+	// 再次运行无需迁移
+	again, err := Run(dir, false)
+	if err != nil || again.From != again.To || len(again.Steps) != 0 {
+		t.Errorf("再次运行 = %+v, %v", again, err)
+	}
+}
+
+func TestRunNewDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+
+	for _, dryRun := range []bool{true, false} {
+		result, err := Run(dir, dryRun)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Created || result.From != CurrentVersion() {
+			t.Errorf("dryRun=%v: Result = %+v", dryRun, result)
+		}
+	}
+	if manifest, found, _ := loadManifest(dir); !found || manifest.Version != CurrentVersion() {
+		t.Errorf("新目录应写入最新版本的 manifest: %+v", manifest)
+	}
+}
+
+func TestRunNewerVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, ManifestFileName), `{"version": 99}`)
+	if _, err := Run(dir, false); err == nil || !strings.Contains(err.Error(), "请升级程序") {
+		t.Errorf("Run() 错误 = %v", err)
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		want     []string
+	}{
+		{
+			name:     "未超过上限",
+			existing: []string{"layout-v0-20250101-090000", "layout-v1-20250102-090000"},
+			want:     []string{"layout-v0-20250101-090000", "layout-v1-20250102-090000"},
+		},
+		{
+			name: "按时间删除最早的",
+			existing: []string{
+				"layout-v2-20250103-090000",
+				"layout-v0-20250104-090000", // 版本号较小但时间较新
+				"layout-v1-20250101-090000",
+				"layout-v1-20250102-090000",
+				"manual", // 不是迁移备份，保留
+			},
+			want: []string{"layout-v0-20250104-090000", "layout-v1-20250102-090000", "layout-v2-20250103-090000", "manual"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.existing {
+				writeTestFile(t, filepath.Join(dir, name, "cookies.json"), "[]")
+			}
+
+			pruneBackups(dir, 3)
+
+			entries, _ := os.ReadDir(dir)
+			var got []string
+			for _, entry := range entries {
+				got = append(got, entry.Name())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("保留的备份\n得到：%v\n期望：%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// listFiles 返回目录下所有文件的相对路径
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	return files
+}
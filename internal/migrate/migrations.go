@@ -0,0 +1,74 @@
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// migrations 按版本排列的迁移，只能追加，已发布的迁移不要修改
+//
+// 迁移里使用的文件名都写成字面量，后续即使相关常量改名也不影响旧迁移
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "Cookie 和浏览器配置移动到 profiles/default/",
+		Apply:       moveToDefaultProfile,
+	},
+	{
+		Version:     2,
+		Description: "cookies.json 改为带保存时间的格式",
+		Apply:       wrapCookies,
+	},
+}
+
+// moveToDefaultProfile 将数据目录根部的 cookies.json 和 browser_profile/ 移动到默认账号配置下
+func moveToDefaultProfile(r *Runner) error {
+	profile := filepath.Join("profiles", "default")
+	for _, name := range []string{"cookies.json", "browser_profile"} {
+		if !r.Exists(name) {
+			continue
+		}
+		if err := r.Move(name, filepath.Join(profile, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cookiesV2 版本 2 的 cookies.json 格式
+type cookiesV2 struct {
+	SavedAt time.Time         `json:"saved_at"`
+	Cookies []json.RawMessage `json:"cookies"`
+}
+
+// wrapCookies 将旧版本的 Cookie 数组转为 {saved_at, cookies}，保存时间取文件修改时间
+func wrapCookies(r *Runner) error {
+	for _, rel := range r.Glob(filepath.Join("profiles", "*", "cookies.json")) {
+		data, err := r.ReadFile(rel)
+		if err != nil {
+			return err
+		}
+
+		var cookies []json.RawMessage
+		if err := json.Unmarshal(data, &cookies); err != nil {
+			// 不是数组，说明已经是新格式
+			continue
+		}
+
+		file := cookiesV2{SavedAt: time.Now(), Cookies: cookies}
+		if info, err := os.Stat(r.abs(rel)); err == nil {
+			file.SavedAt = info.ModTime()
+		}
+		out, err := json.MarshalIndent(file, "", "  ")
+		if err != nil {
+			return fmt.Errorf("转换 %s 失败: %w", rel, err)
+		}
+		if err := r.WriteFile(rel, "转换格式", out, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+package paths
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// AppName 各目录下的应用子目录名
+const AppName = "yst_go_mcp"
+
+// 覆盖默认目录的环境变量
+const (
+	ConfigDirEnv = "YST_CONFIG_DIR"
+	DataDirEnv   = "YST_DATA_DIR"
+	CacheDirEnv  = "YST_CACHE_DIR"
+	StateDirEnv  = "YST_STATE_DIR"
+	OutputDirEnv = "YST_OUTPUT_DIR"
+	ProfileEnv   = "YST_PROFILE"
+)
+
+// 账号配置：每个账号的 Cookie 和浏览器配置保存在数据目录的 profiles/<名称>/ 下
+const (
+	ProfilesDirName = "profiles"
+	DefaultProfile  = "default"
+)
+
+// legacyDir 旧版本使用的数据目录（相对用户主目录）
+var legacyDir = filepath.Join(".yst_go_mcp", "data")
+
+// Dirs 应用使用的各类目录
+type Dirs struct {
+	Config string // 用户配置：draft.json、keywords.json、holidays.json、calendar/
+	Data   string // 持久数据：Cookie、浏览器配置、搜索索引、导出文件的历史版本
+	Cache  string // 可随时删除的缓存
+	State  string // 运行状态：后台任务、采集检查点
+	Output string // 导出文件的默认目录
+
+	Profile string // 账号配置名，默认 default
+}
+
+var (
+	mu        sync.Mutex
+	overrides Dirs
+	resolved  *Dirs
+)
+
+// Override 设置命令行参数指定的目录，优先级高于环境变量，空字段表示不覆盖
+func Override(d Dirs) {
+	mu.Lock()
+	defer mu.Unlock()
+	overrides = d
+	resolved = nil
+}
+
+// Config 返回配置目录
+func Config() string { return get().Config }
+
+// Data 返回数据目录
+func Data() string { return get().Data }
+
+// Cache 返回缓存目录
+func Cache() string { return get().Cache }
+
+// State 返回状态目录
+func State() string { return get().State }
+
+// Output 返回默认输出目录
+func Output() string { return get().Output }
+
+// Profile 返回当前账号配置名
+func Profile() string { return get().Profile }
+
+// ProfileDir 返回当前账号配置的目录：<数据目录>/profiles/<名称>
+func ProfileDir() string {
+	d := get()
+	return filepath.Join(d.Data, ProfilesDirName, d.Profile)
+}
+
+// All 返回所有目录
+func All() Dirs { return get() }
+
+// get 解析目录并缓存结果
+func get() Dirs {
+	mu.Lock()
+	defer mu.Unlock()
+	if resolved == nil {
+		d := resolve()
+		resolved = &d
+	}
+	return *resolved
+}
+
+// resolve 按 命令行参数 > 环境变量 > 平台默认 的顺序解析目录
+func resolve() Dirs {
+	def := defaults()
+	return Dirs{
+		Config: pick(overrides.Config, ConfigDirEnv, def.Config),
+		Data:   pick(overrides.Data, DataDirEnv, def.Data),
+		Cache:  pick(overrides.Cache, CacheDirEnv, def.Cache),
+		State:  pick(overrides.State, StateDirEnv, def.State),
+		Output: pick(overrides.Output, OutputDirEnv, def.Output),
+
+		Profile: profileName(overrides.Profile),
+	}
+}
+
+// profileName 返回账号配置名：命令行参数 > 环境变量 > default，名称不能包含路径分隔符
+func profileName(flagValue string) string {
+	name := strings.TrimSpace(flagValue)
+	if name == "" {
+		name = strings.TrimSpace(os.Getenv(ProfileEnv))
+	}
+	if name == "" {
+		return DefaultProfile
+	}
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\:`) {
+		log.Printf("⚠️ 账号配置名无效: %q，使用 %s", name, DefaultProfile)
+		return DefaultProfile
+	}
+	return name
+}
+
+// pick 返回第一个非空的目录，并展开 ~、转为绝对路径
+func pick(flagValue, env, fallback string) string {
+	dir := flagValue
+	if dir == "" {
+		dir = os.Getenv(env)
+	}
+	if dir == "" {
+		return fallback
+	}
+	dir = ExpandHome(dir)
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return dir
+}
+
+// defaults 返回平台默认目录：
+//   - Linux 遵循 XDG 规范（$XDG_CONFIG_HOME、$XDG_DATA_HOME、$XDG_CACHE_HOME、$XDG_STATE_HOME、XDG_DOWNLOAD_DIR）
+//   - macOS / Windows 沿用 ~/.yst_go_mcp/data，输出到下载目录（macOS）或桌面（Windows）
+//
+// Linux 上如果已有旧版本的 ~/.yst_go_mcp/data 且 XDG 数据目录还不存在，继续使用旧目录，避免丢失登录状态
+func defaults() Dirs {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		dir := filepath.Join(os.TempDir(), AppName)
+		log.Printf("⚠️ 无法获取用户主目录，使用临时目录: %s (%v)", dir, err)
+		return Dirs{Config: dir, Data: dir, Cache: filepath.Join(dir, "cache"), State: dir, Output: dir}
+	}
+
+	cache := filepath.Join(home, ".cache", AppName)
+	if dir, err := os.UserCacheDir(); err == nil {
+		cache = filepath.Join(dir, AppName)
+	}
+
+	legacy := filepath.Join(home, legacyDir)
+	if runtime.GOOS != "linux" {
+		return Dirs{Config: legacy, Data: legacy, Cache: cache, State: legacy, Output: outputDir(home)}
+	}
+
+	data := xdgDir("XDG_DATA_HOME", home, ".local/share")
+	if isDir(legacy) && !isDir(data) {
+		log.Printf("使用旧版本数据目录: %s（可设置 %s 指定其他目录）", legacy, DataDirEnv)
+		return Dirs{Config: legacy, Data: legacy, Cache: cache, State: legacy, Output: outputDir(home)}
+	}
+
+	return Dirs{
+		Config: xdgDir("XDG_CONFIG_HOME", home, ".config"),
+		Data:   data,
+		Cache:  cache,
+		State:  xdgDir("XDG_STATE_HOME", home, ".local/state"),
+		Output: outputDir(home),
+	}
+}
+
+// xdgDir 返回 XDG 基础目录下的应用目录，环境变量未设置或不是绝对路径时使用默认值
+func xdgDir(env, home, fallback string) string {
+	base := os.Getenv(env)
+	if !filepath.IsAbs(base) {
+		base = filepath.Join(home, fallback)
+	}
+	return filepath.Join(base, AppName)
+}
+
+// outputDir 返回默认输出目录：Windows 为桌面，Linux 为 XDG 下载目录，其他为 ~/Downloads，不存在时退回主目录
+func outputDir(home string) string {
+	var dir string
+	switch runtime.GOOS {
+	case "windows":
+		dir = filepath.Join(home, "Desktop")
+	case "linux":
+		dir = xdgDownloadDir(home)
+	default:
+		dir = filepath.Join(home, "Downloads")
+	}
+	if isDir(dir) {
+		return dir
+	}
+	return home
+}
+
+// xdgDownloadDir 读取 XDG_DOWNLOAD_DIR：先看环境变量，再看 $XDG_CONFIG_HOME/user-dirs.dirs
+func xdgDownloadDir(home string) string {
+	if dir := os.Getenv("XDG_DOWNLOAD_DIR"); dir != "" {
+		return expandVars(dir, home)
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if !filepath.IsAbs(configHome) {
+		configHome = filepath.Join(home, ".config")
+	}
+	f, err := os.Open(filepath.Join(configHome, "user-dirs.dirs"))
+	if err != nil {
+		return filepath.Join(home, "Downloads")
+	}
+	defer f.Close()
+
+	// 格式：XDG_DOWNLOAD_DIR="$HOME/Downloads"
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || key != "XDG_DOWNLOAD_DIR" {
+			continue
+		}
+		return expandVars(strings.Trim(value, `"`), home)
+	}
+	return filepath.Join(home, "Downloads")
+}
+
+// expandVars 展开 user-dirs.dirs 中的 $HOME，相对路径按主目录处理
+func expandVars(dir, home string) string {
+	dir = strings.ReplaceAll(strings.ReplaceAll(dir, "${HOME}", home), "$HOME", home)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(home, dir)
+	}
+	return filepath.Clean(dir)
+}
+
+// ExpandHome 展开路径开头的 ~
+func ExpandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
+
+// isDir 判断目录是否存在
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
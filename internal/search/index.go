@@ -0,0 +1,367 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/collector"
+	"github.com/Xuzan9396/yst_go_mcp/internal/fileutil"
+)
+
+// IndexFileName 数据目录下的索引文件
+const IndexFileName = "search_index.json"
+
+// snippetRadius 摘要中命中词前后保留的字符数
+const snippetRadius = 40
+
+// Doc 索引中的一份报告
+type Doc struct {
+	Key  string `json:"key"`
+	Type string `json:"type,omitempty"`
+	Date string `json:"date,omitempty"`
+	Text string `json:"text"`
+	Link string `json:"link,omitempty"`
+}
+
+// Hit 搜索结果
+type Hit struct {
+	Doc     Doc
+	Score   float64
+	Snippet string
+}
+
+// Query 搜索条件
+type Query struct {
+	Text      string
+	StartDate string // YYYY-MM-DD，可选
+	EndDate   string // YYYY-MM-DD，可选
+	Limit     int
+}
+
+// updateMu 串行化索引文件的读-改-写，避免并发的采集任务互相覆盖对方新增的报告
+var updateMu sync.Mutex
+
+// Index 倒排索引：中文按二元组切分，ASCII 按单词切分
+type Index struct {
+	path     string
+	docs     []Doc
+	keys     map[string]int
+	postings map[string]map[int][]int // 词项 -> 文档序号 -> 升序的出现位置
+	lengths  []int
+	avgLen   float64 // 平均文档长度（词项数），BM25 使用
+}
+
+// Open 打开数据目录中的索引，文件不存在时返回空索引
+func Open(dataDir string) (*Index, error) {
+	idx := &Index{path: filepath.Join(dataDir, IndexFileName)}
+
+	data, err := os.ReadFile(idx.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取搜索索引失败: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &idx.docs); err != nil {
+			return nil, fmt.Errorf("解析搜索索引失败: %w", err)
+		}
+	}
+
+	idx.rebuild()
+	return idx, nil
+}
+
+// Update 打开索引、调用 fn 修改后保存，同一进程内的多次更新依次执行
+func Update(dataDir string, fn func(idx *Index)) (*Index, error) {
+	updateMu.Lock()
+	defer updateMu.Unlock()
+
+	idx, err := Open(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	fn(idx)
+	if err := idx.Save(); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// Len 返回索引中的文档数
+func (idx *Index) Len() int {
+	return len(idx.docs)
+}
+
+// AddReports 将报告加入索引，相同链接（或同日期同内容）的报告会被更新而不是重复添加
+func (idx *Index) AddReports(reportType string, reports []collector.Report) int {
+	added := 0
+	for _, r := range reports {
+		doc := Doc{Type: reportType, Text: r.Text, Link: r.Link}
+		if !r.Date.IsZero() {
+			doc.Date = r.Date.Format("2006-01-02")
+		}
+		doc.Key = r.Link
+		if doc.Key == "" {
+			doc.Key = doc.Date + "|" + doc.Text
+		}
+
+		if i, ok := idx.keys[doc.Key]; ok {
+			idx.docs[i] = doc
+			continue
+		}
+		idx.docs = append(idx.docs, doc)
+		added++
+	}
+
+	idx.rebuild()
+	return added
+}
+
+// Save 保存索引到数据目录
+func (idx *Index) Save() error {
+	data, err := json.Marshal(idx.docs)
+	if err != nil {
+		return fmt.Errorf("序列化搜索索引失败: %w", err)
+	}
+	if err := fileutil.WriteFile(idx.path, data, 0644); err != nil {
+		return fmt.Errorf("保存搜索索引失败: %w", err)
+	}
+	return nil
+}
+
+// Search 执行查询：引号内为短语，其余为关键词，所有条件都需命中，按 BM25 排序
+func (idx *Index) Search(q Query) []Hit {
+	phrases, words := parseQuery(q.Text)
+	parts := append(phrases, words...)
+
+	var terms []string
+	for _, p := range parts {
+		for _, tok := range Bigrams(p) {
+			terms = append(terms, tok.Term)
+		}
+	}
+
+	var hits []Hit
+	for i, doc := range idx.docs {
+		if q.StartDate != "" && (doc.Date == "" || doc.Date < q.StartDate) {
+			continue
+		}
+		if q.EndDate != "" && (doc.Date == "" || doc.Date > q.EndDate) {
+			continue
+		}
+
+		lower := strings.ToLower(doc.Text)
+		matched := true
+		for _, p := range parts {
+			if !idx.contains(i, lower, p) {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		hits = append(hits, Hit{
+			Doc:     doc,
+			Score:   idx.score(i, terms),
+			Snippet: snippet(doc.Text, parts),
+		})
+	}
+
+	sort.SliceStable(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		return hits[a].Doc.Date > hits[b].Doc.Date
+	})
+	if q.Limit > 0 && len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+	return hits
+}
+
+// contains 判断文档是否包含查询片段：用倒排表检查词项位置是否相邻
+func (idx *Index) contains(doc int, lowerText, part string) bool {
+	toks := Bigrams(part)
+	if len(toks) == 0 {
+		// 单个汉字等无法切出词项的查询，直接匹配原文
+		return strings.Contains(lowerText, strings.ToLower(part))
+	}
+
+	// 短语中相邻词项的位置差必须与查询一致
+	first := idx.positions(toks[0].Term, doc)
+	for _, start := range first {
+		ok := true
+		for _, tok := range toks[1:] {
+			if !hasPosition(idx.positions(tok.Term, doc), start+tok.Pos-toks[0].Pos) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// positions 返回词项在文档中的位置
+func (idx *Index) positions(term string, doc int) []int {
+	return idx.postings[term][doc]
+}
+
+// score 计算 BM25 分数
+func (idx *Index) score(doc int, terms []string) float64 {
+	const k1, b = 1.2, 0.75
+	score := 0.0
+	n := float64(len(idx.docs))
+	for _, term := range terms {
+		df := float64(len(idx.postings[term]))
+		tf := float64(len(idx.positions(term, doc)))
+		if df == 0 || tf == 0 {
+			continue
+		}
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		norm := tf * (k1 + 1) / (tf + k1*(1-b+b*float64(idx.lengths[doc])/math.Max(idx.avgLen, 1)))
+		score += idf * norm
+	}
+	return score
+}
+
+// rebuild 根据文档重建倒排表
+func (idx *Index) rebuild() {
+	idx.keys = make(map[string]int, len(idx.docs))
+	idx.postings = make(map[string]map[int][]int)
+	idx.lengths = make([]int, len(idx.docs))
+	total := 0
+
+	for i, doc := range idx.docs {
+		idx.keys[doc.Key] = i
+		positions := make(map[string][]int)
+		toks := Bigrams(doc.Text)
+		for _, tok := range toks {
+			positions[tok.Term] = append(positions[tok.Term], tok.Pos)
+		}
+		for term, pos := range positions {
+			if idx.postings[term] == nil {
+				idx.postings[term] = make(map[int][]int)
+			}
+			idx.postings[term][i] = pos
+		}
+		idx.lengths[i] = len(toks)
+		total += len(toks)
+	}
+
+	idx.avgLen = 0
+	if len(idx.docs) > 0 {
+		idx.avgLen = float64(total) / float64(len(idx.docs))
+	}
+}
+
+// Token 词项及其在文本中的位置（按字符计）
+type Token struct {
+	Term string
+	Pos  int
+}
+
+// Bigrams 切分词项：连续汉字生成二元组，ASCII 字母数字按单词切分并转小写。
+// 只用二元组并记录位置，短语查询靠相邻位置匹配；关键词统计使用 analysis.NGrams
+func Bigrams(text string) []Token {
+	var tokens []Token
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.Is(unicode.Han, r):
+			if i+1 < len(runes) && unicode.Is(unicode.Han, runes[i+1]) {
+				tokens = append(tokens, Token{Term: string(runes[i : i+2]), Pos: i})
+			}
+			i++
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			j := i
+			for j < len(runes) && runes[j] < unicode.MaxASCII && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			tokens = append(tokens, Token{Term: strings.ToLower(string(runes[i:j])), Pos: i})
+			i = j
+		default:
+			i++
+		}
+	}
+	return tokens
+}
+
+// parseQuery 拆分查询：双引号内为短语，其余按空白分隔
+func parseQuery(q string) (phrases, words []string) {
+	q = strings.NewReplacer("“", `"`, "”", `"`).Replace(q)
+	parts := strings.Split(q, `"`)
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if i%2 == 1 {
+			phrases = append(phrases, part)
+		} else {
+			words = append(words, strings.Fields(part)...)
+		}
+	}
+	return phrases, words
+}
+
+// snippet 截取第一个命中位置附近的文本，并用 ** 标记命中词
+func snippet(text string, parts []string) string {
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+
+	pos, length := -1, 0
+	for _, p := range parts {
+		pr := []rune(strings.ToLower(p))
+		if i := runeIndex(lower, pr); i >= 0 && (pos < 0 || i < pos) {
+			pos, length = i, len(pr)
+		}
+	}
+	if pos < 0 {
+		pos = 0
+	}
+
+	start := max(pos-snippetRadius, 0)
+	end := min(pos+length+snippetRadius, len(runes))
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	b.WriteString(string(runes[start:pos]))
+	if length > 0 {
+		b.WriteString("**" + string(runes[pos:pos+length]) + "**")
+	}
+	b.WriteString(string(runes[pos+length : end]))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// runeIndex 按字符查找子串位置
+func runeIndex(s, sub []rune) int {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if string(s[i:i+len(sub)]) == string(sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasPosition 判断位置是否存在，positions 为升序
+func hasPosition(positions []int, pos int) bool {
+	i := sort.SearchInts(positions, pos)
+	return i < len(positions) && positions[i] == pos
+}
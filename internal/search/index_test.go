@@ -0,0 +1,41 @@
+package search
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/collector"
+)
+
+// 并发更新时每个任务新增的报告都应保存下来，不会被其他任务覆盖
+func TestUpdateConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	const workers = 8
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			report := collector.Report{
+				Text: fmt.Sprintf("2025-03-%02d 日报 任务%d", i+1, i),
+				Link: fmt.Sprintf("/view?id=%d", i),
+				Date: time.Date(2025, 3, i+1, 0, 0, 0, 0, time.Local),
+			}
+			if _, err := Update(dir, func(idx *Index) { idx.AddReports("日报", []collector.Report{report}) }); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	idx, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx.Len() != workers {
+		t.Errorf("索引中有 %d 份报告，期望 %d 份", idx.Len(), workers)
+	}
+}
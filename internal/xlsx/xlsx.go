@@ -0,0 +1,370 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Xuzan9396/yst_go_mcp/internal/xmlutil"
+)
+
+// maxCellText Excel 单元格最多 32767 个字符
+const maxCellText = 32767
+
+// maxSheetName 工作表名称最多 31 个字符
+const maxSheetName = 31
+
+// Style 单元格样式，对应 styles.xml 中 cellXfs 的序号
+type Style int
+
+// 内置样式
+const (
+	StyleDefault     Style = iota
+	StyleHeader            // 表头：加粗、底色、居中、边框
+	StyleText              // 正文：边框、顶端对齐、自动换行
+	StylePercent           // 百分比 0%
+	StylePercent1          // 百分比 0.0%
+	StyleLink              // 超链接：蓝色下划线
+	StyleBold              // 加粗（合计行）
+	StyleBoldPercent       // 加粗百分比（合计行）
+)
+
+type cellKind int
+
+const (
+	kindEmpty cellKind = iota
+	kindText
+	kindNumber
+	kindFormula
+)
+
+// Cell 单元格
+type Cell struct {
+	kind    cellKind
+	text    string
+	number  float64
+	formula string
+	link    string
+	style   Style
+}
+
+// Text 文本单元格
+func Text(s string) Cell {
+	return Cell{kind: kindText, text: s, style: StyleText}
+}
+
+// Number 数字单元格
+func Number(n float64) Cell {
+	return Cell{kind: kindNumber, number: n, style: StyleText}
+}
+
+// Formula 公式单元格，cached 为打开前显示的计算结果（Excel 打开时会重新计算）
+func Formula(expr string, cached float64) Cell {
+	return Cell{kind: kindFormula, formula: strings.TrimPrefix(expr, "="), number: cached, style: StyleText}
+}
+
+// Link 超链接单元格，url 为空时等同于 Text
+func Link(text, url string) Cell {
+	if url == "" {
+		return Text(text)
+	}
+	return Cell{kind: kindText, text: text, link: url, style: StyleLink}
+}
+
+// Empty 空单元格（保留边框）
+func Empty() Cell {
+	return Cell{kind: kindEmpty, style: StyleText}
+}
+
+// WithStyle 返回设置了样式的单元格
+func (c Cell) WithStyle(s Style) Cell {
+	c.style = s
+	return c
+}
+
+// Sheet 工作表
+type Sheet struct {
+	name       string
+	rows       [][]Cell
+	widths     map[int]float64
+	freezeRows int
+}
+
+// Name 返回工作表名称
+func (s *Sheet) Name() string {
+	return s.name
+}
+
+// AddRow 追加一行，返回行号（从 1 开始）
+func (s *Sheet) AddRow(cells ...Cell) int {
+	s.rows = append(s.rows, cells)
+	return len(s.rows)
+}
+
+// SetColWidth 设置列宽（col 从 0 开始，单位为字符数）
+func (s *Sheet) SetColWidth(col int, width float64) {
+	s.widths[col] = width
+}
+
+// FreezeRows 冻结前 n 行（通常是表头）
+func (s *Sheet) FreezeRows(n int) {
+	s.freezeRows = n
+}
+
+// Workbook 工作簿
+type Workbook struct {
+	sheets []*Sheet
+}
+
+// New 创建空工作簿
+func New() *Workbook {
+	return &Workbook{}
+}
+
+// AddSheet 添加工作表，名称中的非法字符会被替换，重名时自动加序号
+func (wb *Workbook) AddSheet(name string) *Sheet {
+	name = strings.NewReplacer("[", "(", "]", ")", ":", "-", "*", "_", "?", "_", "/", "-", `\`, "-").Replace(name)
+	name = strings.Trim(name, "'")
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", len(wb.sheets)+1)
+	}
+	name = truncate(name, maxSheetName)
+
+	unique := name
+	for i := 2; wb.hasSheet(unique); i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		unique = truncate(name, maxSheetName-len([]rune(suffix))) + suffix
+	}
+
+	s := &Sheet{name: unique, widths: make(map[int]float64)}
+	wb.sheets = append(wb.sheets, s)
+	return s
+}
+
+// hasSheet 判断工作表名称是否已存在（不区分大小写）
+func (wb *Workbook) hasSheet(name string) bool {
+	for _, s := range wb.sheets {
+		if strings.EqualFold(s.name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Write 以 XLSX（Office Open XML）格式写出工作簿
+func (wb *Workbook) Write(w io.Writer) error {
+	if len(wb.sheets) == 0 {
+		wb.AddSheet("Sheet1")
+	}
+
+	z := zip.NewWriter(w)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", wb.contentTypes()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", wb.workbookXML()},
+		{"xl/_rels/workbook.xml.rels", wb.workbookRels()},
+		{"xl/styles.xml", stylesXML},
+	}
+	for i, s := range wb.sheets {
+		sheetXML, rels := s.xml()
+		files = append(files, struct{ name, content string }{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheetXML})
+		if rels != "" {
+			files = append(files, struct{ name, content string }{fmt.Sprintf("xl/worksheets/_rels/sheet%d.xml.rels", i+1), rels})
+		}
+	}
+
+	for _, f := range files {
+		fw, err := z.Create(f.name)
+		if err != nil {
+			return fmt.Errorf("写入 %s 失败: %w", f.name, err)
+		}
+		if _, err := io.WriteString(fw, f.content); err != nil {
+			return fmt.Errorf("写入 %s 失败: %w", f.name, err)
+		}
+	}
+	return z.Close()
+}
+
+// contentTypes 生成 [Content_Types].xml
+func (wb *Workbook) contentTypes() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range wb.sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+// workbookXML 生成 xl/workbook.xml，打开时强制重新计算公式
+func (wb *Workbook) workbookXML() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, s := range wb.sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlutil.Escape(s.name), i+1, i+1)
+	}
+	b.WriteString(`</sheets><calcPr calcId="191029" fullCalcOnLoad="1"/></workbook>`)
+	return b.String()
+}
+
+// workbookRels 生成 xl/_rels/workbook.xml.rels
+func (wb *Workbook) workbookRels() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range wb.sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(wb.sheets)+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// xml 生成工作表 XML 和超链接关系文件（没有超链接时为空）
+func (s *Sheet) xml() (string, string) {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+
+	if s.freezeRows > 0 {
+		fmt.Fprintf(&b, `<sheetViews><sheetView workbookViewId="0"><pane ySplit="%d" topLeftCell="A%d" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`,
+			s.freezeRows, s.freezeRows+1)
+	}
+
+	if len(s.widths) > 0 {
+		cols := make([]int, 0, len(s.widths))
+		for col := range s.widths {
+			cols = append(cols, col)
+		}
+		sort.Ints(cols)
+		b.WriteString(`<cols>`)
+		for _, col := range cols {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%s" customWidth="1"/>`, col+1, col+1, formatNumber(s.widths[col]))
+		}
+		b.WriteString(`</cols>`)
+	}
+
+	type hyperlink struct{ ref, url string }
+	var links []hyperlink
+
+	b.WriteString(`<sheetData>`)
+	for i, row := range s.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, i+1)
+		for j, c := range row {
+			ref := CellRef(j, i+1)
+			switch c.kind {
+			case kindEmpty:
+				fmt.Fprintf(&b, `<c r="%s" s="%d"/>`, ref, c.style)
+			case kindText:
+				fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`,
+					ref, c.style, xmlutil.Escape(truncate(c.text, maxCellText)))
+			case kindNumber:
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, c.style, formatNumber(c.number))
+			case kindFormula:
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><f>%s</f><v>%s</v></c>`, ref, c.style, xmlutil.Escape(c.formula), formatNumber(c.number))
+			}
+			if c.link != "" {
+				links = append(links, hyperlink{ref, c.link})
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData>`)
+
+	var rels string
+	if len(links) > 0 {
+		var r strings.Builder
+		r.WriteString(xmlHeader)
+		r.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+		b.WriteString(`<hyperlinks>`)
+		for i, l := range links {
+			fmt.Fprintf(&b, `<hyperlink ref="%s" r:id="rId%d"/>`, l.ref, i+1)
+			fmt.Fprintf(&r, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="%s" TargetMode="External"/>`,
+				i+1, xmlutil.Escape(l.url))
+		}
+		b.WriteString(`</hyperlinks>`)
+		r.WriteString(`</Relationships>`)
+		rels = r.String()
+	}
+
+	b.WriteString(`<pageMargins left="0.7" right="0.7" top="0.75" bottom="0.75" header="0.3" footer="0.3"/></worksheet>`)
+	return b.String(), rels
+}
+
+// CellRef 返回单元格引用，col 从 0 开始、row 从 1 开始，例如 CellRef(2, 5) = "C5"
+func CellRef(col, row int) string {
+	return ColName(col) + strconv.Itoa(row)
+}
+
+// ColName 返回列名，col 从 0 开始：0 -> A，26 -> AA
+func ColName(col int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name
+}
+
+// formatNumber 格式化数字，去掉多余的 0
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// truncate 按字符截断
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const rootRels = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// stylesXML cellXfs 的顺序必须与 Style 常量一致
+const stylesXML = xmlHeader + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="0.0%"/></numFmts>` +
+	`<fonts count="3">` +
+	`<font><sz val="11"/><name val="等线"/><family val="2"/><charset val="134"/></font>` +
+	`<font><b/><sz val="11"/><name val="等线"/><family val="2"/><charset val="134"/></font>` +
+	`<font><u/><sz val="11"/><color rgb="FF0563C1"/><name val="等线"/><family val="2"/><charset val="134"/></font>` +
+	`</fonts>` +
+	`<fills count="3">` +
+	`<fill><patternFill patternType="none"/></fill>` +
+	`<fill><patternFill patternType="gray125"/></fill>` +
+	`<fill><patternFill patternType="solid"><fgColor rgb="FFD9E1F2"/><bgColor indexed="64"/></patternFill></fill>` +
+	`</fills>` +
+	`<borders count="2">` +
+	`<border><left/><right/><top/><bottom/><diagonal/></border>` +
+	`<border><left style="thin"><color auto="1"/></left><right style="thin"><color auto="1"/></right>` +
+	`<top style="thin"><color auto="1"/></top><bottom style="thin"><color auto="1"/></bottom><diagonal/></border>` +
+	`</borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="8">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="2" borderId="1" xfId="0" applyFont="1" applyFill="1" applyBorder="1" applyAlignment="1"><alignment horizontal="center" vertical="center" wrapText="1"/></xf>` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="1" xfId="0" applyBorder="1" applyAlignment="1"><alignment vertical="top" wrapText="1"/></xf>` +
+	`<xf numFmtId="9" fontId="0" fillId="0" borderId="1" xfId="0" applyNumberFormat="1" applyBorder="1" applyAlignment="1"><alignment horizontal="center" vertical="top"/></xf>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="1" xfId="0" applyNumberFormat="1" applyBorder="1" applyAlignment="1"><alignment horizontal="center" vertical="top"/></xf>` +
+	`<xf numFmtId="0" fontId="2" fillId="0" borderId="1" xfId="0" applyFont="1" applyBorder="1" applyAlignment="1"><alignment vertical="top"/></xf>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="1" xfId="0" applyFont="1" applyBorder="1" applyAlignment="1"><alignment vertical="top"/></xf>` +
+	`<xf numFmtId="9" fontId="1" fillId="0" borderId="1" xfId="0" applyNumberFormat="1" applyFont="1" applyBorder="1" applyAlignment="1"><alignment horizontal="center" vertical="top"/></xf>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
@@ -0,0 +1,162 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestColName(t *testing.T) {
+	tests := []struct {
+		col  int
+		want string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+	for _, tt := range tests {
+		if got := ColName(tt.col); got != tt.want {
+			t.Errorf("ColName(%d) = %s，期望 %s", tt.col, got, tt.want)
+		}
+	}
+	if got := CellRef(2, 5); got != "C5" {
+		t.Errorf("CellRef(2, 5) = %s，期望 C5", got)
+	}
+}
+
+func TestAddSheetName(t *testing.T) {
+	wb := New()
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"2025-03 汇总", "2025-03 汇总"},
+		{"a/b:c*d?e[f]", "a-b-c_d_e(f)"},
+		{"'引号'", "引号"},
+		{"", "Sheet4"},
+		{"2025-03 汇总", "2025-03 汇总 (2)"},
+		{"2025-03 汇总", "2025-03 汇总 (3)"},
+		{strings.Repeat("长", 40), strings.Repeat("长", 31)},
+		{strings.Repeat("长", 40), strings.Repeat("长", 27) + " (2)"},
+	}
+	for _, tt := range tests {
+		if got := wb.AddSheet(tt.name).Name(); got != tt.want {
+			t.Errorf("AddSheet(%q) = %q，期望 %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet("汇总")
+	s.SetColWidth(1, 24)
+	s.FreezeRows(1)
+	s.AddRow(Text("任务").WithStyle(StyleHeader), Text("权重").WithStyle(StyleHeader))
+	s.AddRow(Text(`修复 <登录> & "超时"`+"\x01"), Number(0.25).WithStyle(StylePercent))
+	s.AddRow(Link("查看原文", "https://kpi.example.com/view?id=1&type=daily"), Formula("=SUM(B2:B2)", 0.25))
+	s.AddRow(Empty(), Link("无链接", ""))
+	wb.AddSheet("空表")
+
+	var buf bytes.Buffer
+	if err := wb.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	files := readZip(t, buf.Bytes())
+
+	for _, name := range []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels",
+		"xl/styles.xml",
+		"xl/worksheets/sheet1.xml",
+		"xl/worksheets/_rels/sheet1.xml.rels",
+		"xl/worksheets/sheet2.xml",
+	} {
+		content, ok := files[name]
+		if !ok {
+			t.Errorf("缺少 %s", name)
+			continue
+		}
+		if err := wellFormed(content); err != nil {
+			t.Errorf("%s 不是合法的 XML: %v", name, err)
+		}
+	}
+	if _, ok := files["xl/worksheets/_rels/sheet2.xml.rels"]; ok {
+		t.Error("没有超链接的工作表不应生成关系文件")
+	}
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`,
+		`<col min="2" max="2" width="24" customWidth="1"/>`,
+		`<c r="A2" s="2" t="inlineStr"><is><t xml:space="preserve">修复 &lt;登录&gt; &amp; &quot;超时&quot;</t></is></c>`,
+		`<c r="B2" s="3"><v>0.25</v></c>`,
+		`<c r="B3" s="2"><f>SUM(B2:B2)</f><v>0.25</v></c>`,
+		`<c r="A4" s="2"/>`,
+		`<hyperlink ref="A3" r:id="rId1"/>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet1.xml 缺少 %s", want)
+		}
+	}
+	if strings.Contains(sheet, `ref="B4"`) {
+		t.Error("url 为空的链接不应生成超链接")
+	}
+	if rels := files["xl/worksheets/_rels/sheet1.xml.rels"]; !strings.Contains(rels, `Target="https://kpi.example.com/view?id=1&amp;type=daily"`) {
+		t.Errorf("超链接关系 = %s", rels)
+	}
+}
+
+func TestWriteEmptyWorkbook(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New().Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if workbook := readZip(t, buf.Bytes())["xl/workbook.xml"]; !strings.Contains(workbook, `<sheet name="Sheet1"`) {
+		t.Errorf("空工作簿应包含默认工作表: %s", workbook)
+	}
+}
+
+// readZip 读取 zip 中的所有文件
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, f := range z.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+// wellFormed 检查 XML 是否格式正确
+func wellFormed(content string) error {
+	d := xml.NewDecoder(strings.NewReader(content))
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
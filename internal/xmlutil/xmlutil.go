@@ -0,0 +1,23 @@
+package xmlutil
+
+import "strings"
+
+// Escape 转义 XML 特殊字符，并去掉 XML 不允许的控制字符
+func Escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '&':
+			b.WriteString("&amp;")
+		case r == '<':
+			b.WriteString("&lt;")
+		case r == '>':
+			b.WriteString("&gt;")
+		case r == '"':
+			b.WriteString("&quot;")
+		case r == '\t' || r == '\n' || r == '\r' || r >= 0x20 && r != 0xFFFE && r != 0xFFFF:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,21 @@
+package xmlutil
+
+import "testing"
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"修复登录", "修复登录"},
+		{`<a href="x">&</a>`, "&lt;a href=&quot;x&quot;&gt;&amp;&lt;/a&gt;"},
+		{"a\tb\r\nc", "a\tb\r\nc"},
+		{"a\x00b\x08c\x1fd", "abcd"},
+		{"a\ufffeb\uffffc", "abc"},
+		{"'单引号'", "'单引号'"},
+	}
+	for _, tt := range tests {
+		if got := Escape(tt.in); got != tt.want {
+			t.Errorf("Escape(%q) = %q，期望 %q", tt.in, got, tt.want)
+		}
+	}
+}